@@ -0,0 +1,51 @@
+package flags
+
+import "fmt"
+
+// Values the raw values given by the user (more than one for variadic arguments).
+// Only arguments bound to a parse result have values (see ParseResult.Arg)
+func (arg *Arg) Values() []string {
+	return arg.values
+}
+
+// argUsage the argument's representation in the usage line (i.e. SRC, [DST], FILES...)
+func (arg *Arg) argUsage() string {
+	usage := arg.Name
+
+	if arg.Variadic {
+		usage += "..."
+	}
+
+	if !arg.Required {
+		usage = fmt.Sprintf("[%s]", usage)
+	}
+
+	return usage
+}
+
+// validateArgs check the positional arguments' definition
+func validateArgs(args []*Arg) error {
+	optionalFound := false
+
+	for i, arg := range args {
+		if arg.Name == "" {
+			return fmt.Errorf("positional argument #%d has no name", i+1)
+		}
+
+		if arg.Value == nil {
+			return fmt.Errorf(`positional argument "%s" has no value`, arg.Name)
+		}
+
+		if arg.Variadic && i != len(args)-1 {
+			return fmt.Errorf(`positional argument "%s" is variadic, but it is not the last one`, arg.Name)
+		}
+
+		if arg.Required && optionalFound {
+			return fmt.Errorf(`positional argument "%s" is required, but follows an optional one`, arg.Name)
+		}
+
+		optionalFound = optionalFound || !arg.Required
+	}
+
+	return nil
+}
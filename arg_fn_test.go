@@ -0,0 +1,33 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgArgUsage(t *testing.T) {
+	assert.Equal(t, "SRC", (&Arg{Name: "SRC", Required: true}).argUsage())
+	assert.Equal(t, "[DST]", (&Arg{Name: "DST"}).argUsage())
+	assert.Equal(t, "FILES...", (&Arg{Name: "FILES", Required: true, Variadic: true}).argUsage())
+	assert.Equal(t, "[FILES...]", (&Arg{Name: "FILES", Variadic: true}).argUsage())
+}
+
+func TestValidateArgs(t *testing.T) {
+	assert.NoError(t, validateArgs([]*Arg{
+		{Name: "SRC", Value: &String{}, Required: true},
+		{Name: "DST", Value: &String{}},
+		{Name: "EXTRA", Value: &String{}, Variadic: true},
+	}))
+
+	assert.Error(t, validateArgs([]*Arg{{Value: &String{}}}))
+	assert.Error(t, validateArgs([]*Arg{{Name: "SRC"}}))
+	assert.Error(t, validateArgs([]*Arg{
+		{Name: "FILES", Value: &String{}, Variadic: true},
+		{Name: "DST", Value: &String{}},
+	}))
+	assert.Error(t, validateArgs([]*Arg{
+		{Name: "SRC", Value: &String{}},
+		{Name: "DST", Value: &String{}, Required: true},
+	}))
+}
@@ -1,11 +1,18 @@
 package flags
 
-// WithOptions add multiple options at once
+// WithOptions add multiple options at once.
+// It panics if an option's long or short name or an enum's default value is not valid
 func (cmd *Command) WithOptions(opts ...*Option) {
 	if cmd.Options == nil {
 		cmd.Options = []*Option{}
 	}
 
+	for _, opt := range opts {
+		if err := validateOption(opt); err != nil {
+			panic(err)
+		}
+	}
+
 	cmd.Options = append(cmd.Options, opts...)
 }
 
@@ -17,3 +24,34 @@ func (cmd *Command) WithCommands(cmds ...*Command) {
 
 	cmd.SubCommands = append(cmd.SubCommands, cmds...)
 }
+
+// WithArgs add one or more positional arguments at once.
+// It panics if the arguments' definition is not valid
+// (i.e. a variadic argument which is not the last one)
+func (cmd *Command) WithArgs(args ...*Arg) {
+	if cmd.Args == nil {
+		cmd.Args = []*Arg{}
+	}
+
+	if err := validateArgs(append(cmd.Args, args...)); err != nil {
+		panic(err)
+	}
+
+	cmd.Args = append(cmd.Args, args...)
+}
+
+// WithGroups add one or more options' constraints at once.
+// It panics if a group has less than two options
+func (cmd *Command) WithGroups(groups ...*OptionGroup) {
+	if cmd.Groups == nil {
+		cmd.Groups = []*OptionGroup{}
+	}
+
+	for _, group := range groups {
+		if err := validateGroup(group); err != nil {
+			panic(err)
+		}
+	}
+
+	cmd.Groups = append(cmd.Groups, groups...)
+}
@@ -22,3 +22,23 @@ func TestCommandWithCommands(t *testing.T) {
 
 	assert.Len(t, cmd.SubCommands, 2)
 }
+
+func TestCommandWithArgs(t *testing.T) {
+	cmd := Command{}
+	cmd.WithArgs(&Arg{Name: "SRC", Value: &String{}, Required: true})
+	cmd.WithArgs(&Arg{Name: "DST", Value: &String{}})
+
+	assert.Len(t, cmd.Args, 2)
+	assert.Panics(t, func() { cmd.WithArgs(&Arg{Name: "OTHER", Value: &String{}, Required: true}) })
+}
+
+func TestCommandWithGroups(t *testing.T) {
+	json := NewBool("json", EmptyShort, "", false)
+	yaml := NewBool("yaml", EmptyShort, "", false)
+
+	cmd := Command{}
+	cmd.WithGroups(MutuallyExclusive(json, yaml))
+
+	assert.Len(t, cmd.Groups, 1)
+	assert.Panics(t, func() { cmd.WithGroups(OneRequired(json)) })
+}
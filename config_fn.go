@@ -0,0 +1,240 @@
+package flags
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// JSONDecoder configuration files' decoder for JSON files
+type JSONDecoder struct{}
+
+// INIDecoder configuration files' decoder for INI files. Sections map to
+// commands, dot-separated for sub-commands (i.e. [remote.add]), while the
+// repeated keys are lists
+type INIDecoder struct{}
+
+// Decode decode the configuration
+func (fn ConfigDecoderFunc) Decode(reader io.Reader) (map[string]interface{}, error) {
+	return fn(reader)
+}
+
+// Decode decode the configuration
+func (decoder JSONDecoder) Decode(reader io.Reader) (map[string]interface{}, error) {
+	tree := map[string]interface{}{}
+
+	jsonDecoder := json.NewDecoder(reader)
+	jsonDecoder.UseNumber()
+
+	if err := jsonDecoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// Decode decode the configuration
+func (decoder INIDecoder) Decode(reader io.Reader) (map[string]interface{}, error) {
+	tree := map[string]interface{}{}
+	section := tree
+	scanner := bufio.NewScanner(reader)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = tree
+
+			for _, name := range strings.Split(strings.Trim(line, "[]"), ".") {
+				subSection, ok := section[strings.TrimSpace(name)].(map[string]interface{})
+				if !ok {
+					subSection = map[string]interface{}{}
+					section[strings.TrimSpace(name)] = subSection
+				}
+
+				section = subSection
+			}
+
+			continue
+		}
+
+		pair := strings.SplitN(line, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf(`line %d: expected "key = value", got "%s"`, lineNum, line)
+		}
+
+		key := strings.TrimSpace(pair[0])
+		value := strings.TrimSpace(pair[1])
+
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+
+		switch current := section[key].(type) {
+		case nil:
+			section[key] = value
+		case []interface{}:
+			section[key] = append(current, value)
+		default:
+			section[key] = []interface{}{current, value}
+		}
+	}
+
+	return tree, scanner.Err()
+}
+
+// configDecoder the decoder of the configuration file, by extension
+func (flags *Flags) configDecoder(path string) (ConfigDecoder, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	if decoder, ok := flags.ConfigDecoders[ext]; ok {
+		return decoder, nil
+	}
+
+	switch ext {
+	case ".json":
+		return JSONDecoder{}, nil
+	case ".ini":
+		return INIDecoder{}, nil
+	}
+
+	return nil, fmt.Errorf(`no configuration decoder for "%s" files`, ext)
+}
+
+// readConfig read the configuration file
+func (flags *Flags) readConfig(path string) (map[string]interface{}, error) {
+	decoder, err := flags.configDecoder(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	tree, err := decoder.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf(`invalid configuration file "%s": %w`, path, err)
+	}
+
+	return tree, nil
+}
+
+// configValues the string representation of a configuration value (or list of values)
+func configValues(value interface{}) ([]string, error) {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}, nil
+	case bool:
+		return []string{strconv.FormatBool(typed)}, nil
+	case float64:
+		return []string{strconv.FormatFloat(typed, 'f', -1, 64)}, nil
+	case json.Number, int, int64, uint, uint64:
+		return []string{fmt.Sprintf("%v", typed)}, nil
+	case []interface{}:
+		values := []string{}
+
+		for _, item := range typed {
+			itemValues, err := configValues(item)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, itemValues...)
+		}
+
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("unsupported value type %T", value)
+}
+
+// applyConfig set the options given neither as arguments nor as environment
+// variables from the configuration file, if any
+func (p *parser) applyConfig() error {
+	if p.flags.ConfigOption == nil {
+		return nil
+	}
+
+	path := p.result.Value(p.flags.ConfigOption).String()
+	if path == "" {
+		return nil
+	}
+
+	tree, err := p.flags.readConfig(path)
+	// the default configuration file is optional
+	if os.IsNotExist(err) && !p.result.IsSet(p.flags.ConfigOption) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	section := tree
+	levels := append([]*Command{nil}, p.result.path...)
+	keyPrefix := ""
+
+	for _, cmd := range levels {
+		options := p.flags.Options
+
+		if cmd != nil {
+			options = cmd.Options
+			keyPrefix += cmd.Name + "."
+			section, _ = section[cmd.Name].(map[string]interface{})
+		}
+
+		if section == nil {
+			return nil
+		}
+
+		for _, option := range options {
+			if err := p.applyConfigValue(option, section, path, keyPrefix); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyConfigValue set the option from its configuration section, unless already set
+func (p *parser) applyConfigValue(option *Option, section map[string]interface{}, path string, keyPrefix string) error {
+	value, ok := section[option.Long]
+	if option.Long == "" || !ok || value == nil || p.result.IsSet(option) {
+		return nil
+	}
+
+	source := Source{Kind: SourceConfig, File: path, Key: keyPrefix + option.Long}
+
+	values, err := configValues(value)
+	if err != nil {
+		return &InvalidValueError{
+			Token:  source.token(),
+			Value:  fmt.Sprintf("%v", value),
+			Option: option,
+			Path:   p.result.Path(),
+			Err:    err,
+		}
+	}
+
+	for _, value := range values {
+		if err := p.setOption(option, source, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
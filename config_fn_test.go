@@ -0,0 +1,178 @@
+package flags
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfigFile(t *testing.T, name string, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "flags")
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestJSONDecoderDecode(t *testing.T) {
+	tree, err := JSONDecoder{}.Decode(strings.NewReader(`{"num": 3, "build": {"dry-run": true}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "3", string(tree["num"].(json.Number)))
+	assert.Equal(t, map[string]interface{}{"dry-run": true}, tree["build"])
+
+	_, err = JSONDecoder{}.Decode(strings.NewReader(`{"num": `))
+	assert.Error(t, err)
+}
+
+func TestINIDecoderDecode(t *testing.T) {
+	content := `
+; comment
+debug = true
+name = "quoted value"
+
+[build]
+# comment
+tag = a
+tag = b
+
+[remote.add]
+url = https://example.com/?a=b
+`
+	tree, err := INIDecoder{}.Decode(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"debug": "true",
+		"name":  "quoted value",
+		"build": map[string]interface{}{"tag": []interface{}{"a", "b"}},
+		"remote": map[string]interface{}{
+			"add": map[string]interface{}{"url": "https://example.com/?a=b"},
+		},
+	}, tree)
+
+	_, err = INIDecoder{}.Decode(strings.NewReader("invalid line"))
+	assert.Error(t, err)
+}
+
+func TestFlagsParseConfig(t *testing.T) {
+	path, cleanup := testConfigFile(t, "config.json", `{
+		"num": 3,
+		"name": "config",
+		"debug": true,
+		"build": {"dry-run": true, "tags": ["a", "b"], "num": 10}
+	}`)
+	defer cleanup()
+
+	config := NewString("config", 'c', "", "")
+	num := NewInt("num", 'n', "", 1)
+	name := NewString("name", EmptyShort, "", "default")
+	name.EnvVar = "NAME"
+	debug := NewBool("debug", 'd', "", false)
+	dryRun := NewBool("dry-run", EmptyShort, "", false)
+	tags := NewString("tags", EmptyShort, "", "")
+	build := &Command{Name: "build"}
+	build.WithOptions(dryRun, tags)
+
+	flags := Flags{ConfigOption: config}
+	flags.WithOptions(config, num, name, debug)
+	flags.WithCommands(build)
+	flags.LookupEnv = testLookupEnv(map[string]string{"NAME": "env"})
+
+	// flags > env > config file > default
+	result, err := flags.ParseArgs([]string{"--config", path, "--debug=false", "build"})
+	assert.NoError(t, err)
+
+	intVal, _ := IntValue(result.Option(num))
+	assert.Equal(t, 3, intVal)
+
+	strVal, _ := StringValue(result.Option(name))
+	assert.Equal(t, "env", strVal)
+
+	boolVal, _ := BoolValue(result.Option(debug))
+	assert.False(t, boolVal)
+
+	boolVal, _ = BoolValue(result.Option(dryRun))
+	assert.True(t, boolVal)
+
+	// lists are set one by one
+	strVal, _ = StringValue(result.Option(tags))
+	assert.Equal(t, "b", strVal)
+
+	// sections are bound to the called commands only
+	result, err = flags.ParseArgs([]string{"--config", path})
+	assert.NoError(t, err)
+
+	boolVal, _ = BoolValue(result.Option(dryRun))
+	assert.False(t, boolVal)
+}
+
+func TestFlagsParseConfigErrors(t *testing.T) {
+	path, cleanup := testConfigFile(t, "config.json", `{"num": "three", "section": {"a": 1}}`)
+	defer cleanup()
+
+	config := NewString("config", 'c', "", filepath.Join(filepath.Dir(path), "missing.json"))
+	num := NewInt("num", 'n', "", 1)
+	section := NewString("section", EmptyShort, "", "")
+
+	flags := Flags{ConfigOption: config}
+	flags.WithOptions(config, num)
+
+	// the default configuration file is optional
+	_, err := flags.ParseArgs([]string{})
+	assert.NoError(t, err)
+
+	_, err = flags.ParseArgs([]string{"--config", config.Value.DefaultValueString()})
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = flags.ParseArgs([]string{"--config", path})
+
+	var valueErr *InvalidValueError
+
+	assert.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, num, valueErr.Option)
+	assert.Equal(t, path+":num", valueErr.Token)
+
+	_, err = flags.ParseArgs([]string{"--config", path, "--num", "1"})
+	assert.NoError(t, err)
+
+	flags.WithOptions(section)
+	_, err = flags.ParseArgs([]string{"--config", path, "--num", "1"})
+	assert.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, section, valueErr.Option)
+
+	_, err = flags.ParseArgs([]string{"--config", "config.unknown"})
+	assert.Error(t, err)
+}
+
+func TestFlagsConfigDecoders(t *testing.T) {
+	path, cleanup := testConfigFile(t, "config.custom", "num: 5")
+	defer cleanup()
+
+	config := NewString("config", 'c', "", path)
+	num := NewInt("num", 'n', "", 1)
+
+	flags := Flags{ConfigOption: config}
+	flags.WithOptions(config, num)
+	flags.ConfigDecoders = map[string]ConfigDecoder{
+		".custom": ConfigDecoderFunc(func(reader io.Reader) (map[string]interface{}, error) {
+			content, err := ioutil.ReadAll(reader)
+			pair := strings.SplitN(string(content), ": ", 2)
+
+			return map[string]interface{}{pair[0]: pair[1]}, err
+		}),
+	}
+
+	result, err := flags.ParseArgs([]string{})
+	assert.NoError(t, err)
+
+	intVal, _ := IntValue(result.Option(num))
+	assert.Equal(t, 5, intVal)
+}
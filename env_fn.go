@@ -0,0 +1,84 @@
+package flags
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envVar the option's environment variable: either the explicit Option.EnvVar
+// or, if Flags.EnvPrefix is set, the automatic one, made of the prefix, the
+// path of the command the option belongs to and its long name
+// (i.e. MYTOOL_BUILD_DRY_RUN for --dry-run of the build command).
+// The built-in help and print-config options never get an automatic one
+func (flags *Flags) envVar(option *Option) string {
+	if option.EnvVar != "" {
+		return option.EnvVar
+	}
+
+	if flags.EnvPrefix == "" || option.Long == "" || option == HelpOption || option == PrintConfigOption {
+		return ""
+	}
+
+	path, _ := flags.optionPath(option, nil, flags.Options, flags.Commands)
+	parts := []string{flags.EnvPrefix}
+
+	for _, cmd := range path {
+		parts = append(parts, cmd.Name)
+	}
+
+	parts = append(parts, option.Long)
+	name := regexp.MustCompile("[^A-Z0-9]+").ReplaceAllString(strings.ToUpper(strings.Join(parts, "_")), "_")
+
+	return strings.Trim(name, "_")
+}
+
+// optionPath the path of the command the option belongs to (empty for the root)
+func (flags *Flags) optionPath(
+	option *Option, path []*Command, options []*Option, commands []*Command,
+) ([]*Command, bool) {
+	for _, opt := range options {
+		if opt == option {
+			return path, true
+		}
+	}
+
+	for _, cmd := range commands {
+		cmdPath := append(append([]*Command{}, path...), cmd)
+		if found, ok := flags.optionPath(option, cmdPath, cmd.Options, cmd.SubCommands); ok {
+			return found, true
+		}
+	}
+
+	return []*Command{}, false
+}
+
+func (flags *Flags) lookupEnv(key string) (string, bool) {
+	if flags.LookupEnv != nil {
+		return flags.LookupEnv(key)
+	}
+
+	return os.LookupEnv(key)
+}
+
+// applyEnv set the options not given as arguments from their environment variables
+func (p *parser) applyEnv() error {
+	for _, option := range p.resultOptions() {
+		if p.result.IsSet(option) {
+			continue
+		}
+
+		envVar := p.flags.envVar(option)
+		if envVar == "" {
+			continue
+		}
+
+		if value, ok := p.flags.lookupEnv(envVar); ok {
+			if err := p.setOption(option, Source{Kind: SourceEnv, EnvVar: envVar}, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package flags
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+
+		return value, ok
+	}
+}
+
+func TestFlagsEnvVar(t *testing.T) {
+	debug := NewBool("debug", 'd', "", false)
+	dryRun := NewBool("dry-run", EmptyShort, "", false)
+	level := NewString("log.level", EmptyShort, "", "")
+	explicit := NewString("token", EmptyShort, "", "")
+	explicit.EnvVar = "API_TOKEN"
+	short := NewBool("", 'z', "", false)
+	add := &Command{Name: "add"}
+	add.WithOptions(level)
+	remote := &Command{Name: "remote"}
+	remote.WithCommands(add)
+	build := &Command{Name: "build"}
+	build.WithOptions(dryRun)
+
+	flags := Flags{}
+	flags.WithOptions(debug, explicit, short)
+	flags.WithCommands(build, remote)
+
+	assert.Equal(t, "", flags.envVar(debug))
+	assert.Equal(t, "API_TOKEN", flags.envVar(explicit))
+
+	flags.EnvPrefix = "mytool"
+	assert.Equal(t, "MYTOOL_DEBUG", flags.envVar(debug))
+	assert.Equal(t, "MYTOOL_BUILD_DRY_RUN", flags.envVar(dryRun))
+	assert.Equal(t, "MYTOOL_REMOTE_ADD_LOG_LEVEL", flags.envVar(level))
+	assert.Equal(t, "API_TOKEN", flags.envVar(explicit))
+	assert.Equal(t, "", flags.envVar(short))
+}
+
+func TestFlagsParseEnv(t *testing.T) {
+	debug := NewBool("debug", 'd', "", false)
+	debug.Persistent = true
+	num := NewInt("num", 'n', "", 1)
+	num.EnvVar = "NUM"
+	dryRun := NewBool("dry-run", EmptyShort, "", false)
+	build := &Command{Name: "build"}
+	build.WithOptions(dryRun)
+
+	flags := Flags{EnvPrefix: "MYTOOL"}
+	flags.WithOptions(debug, num)
+	flags.WithCommands(build)
+	flags.LookupEnv = testLookupEnv(map[string]string{
+		"MYTOOL_DEBUG":         "true",
+		"MYTOOL_BUILD_DRY_RUN": "true",
+		"NUM":                  "2",
+	})
+
+	result, err := flags.ParseArgs([]string{"build"})
+	assert.NoError(t, err)
+
+	boolVal, _ := BoolValue(result.Option(debug))
+	assert.True(t, boolVal)
+	assert.True(t, result.IsSet(debug))
+
+	boolVal, _ = BoolValue(result.Option(dryRun))
+	assert.True(t, boolVal)
+
+	intVal, _ := IntValue(result.Option(num))
+	assert.Equal(t, 2, intVal)
+
+	// the arguments take precedence
+	result, err = flags.ParseArgs([]string{"--num", "3", "build", "--debug=false"})
+	assert.NoError(t, err)
+
+	intVal, _ = IntValue(result.Option(num))
+	assert.Equal(t, 3, intVal)
+
+	boolVal, _ = BoolValue(result.Option(debug))
+	assert.False(t, boolVal)
+
+	// options of not called commands are not affected
+	result, err = flags.ParseArgs([]string{})
+	assert.NoError(t, err)
+
+	boolVal, _ = BoolValue(result.Option(dryRun))
+	assert.False(t, boolVal)
+
+	flags.LookupEnv = testLookupEnv(map[string]string{"NUM": "two"})
+	_, err = flags.ParseArgs([]string{})
+
+	var valueErr *InvalidValueError
+
+	assert.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, "NUM", valueErr.Token)
+	assert.Equal(t, num, valueErr.Option)
+}
+
+func TestFlagsPrintEnvHelp(t *testing.T) {
+	testWriter := &testStringWriter{}
+
+	flags := Flags{EnvPrefix: "APP"}
+	flags.Init("AppName", "")
+	flags.WithOptions(NewString("str", 's', "String", ""), NewInt("num", 'n', "Number", 1))
+
+	expectedOutput := `AppName
+
+
+Available options.
+
+--str		-s		String (env: APP_STR)
+--num		-n		Number (default value: "1", env: APP_NUM)
+`
+
+	flags.PrintHelpWithArgs([]string{}, testWriter)
+
+	assert.Equal(t, expectedOutput, testWriter.Value)
+}
+
+func TestFlagsEnvBuiltinOptions(t *testing.T) {
+	testWriter := &testStringWriter{}
+	str := NewString("str", 's', "String", "")
+
+	flags := Flags{EnvPrefix: "APP"}
+	flags.Init("AppName", "")
+	flags.WithOptions(str, HelpOption, PrintConfigOption)
+	flags.LookupEnv = testLookupEnv(map[string]string{
+		"APP_STR": "value", "APP_HELP": "true", "APP_PRINT_CONFIG": "1",
+	})
+
+	assert.Equal(t, "", flags.envVar(HelpOption))
+	assert.Equal(t, "", flags.envVar(PrintConfigOption))
+
+	result, err := flags.ParseArgs([]string{})
+	assert.NoError(t, err)
+
+	strVal, _ := StringValue(result.Option(str))
+	assert.Equal(t, "value", strVal)
+	assert.False(t, result.IsSet(PrintConfigOption))
+
+	flags.PrintHelpWithArgs([]string{}, testWriter)
+	assert.Contains(t, testWriter.Value, "env: APP_STR")
+	assert.NotContains(t, testWriter.Value, "APP_HELP")
+	assert.NotContains(t, testWriter.Value, "APP_PRINT_CONFIG")
+}
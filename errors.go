@@ -0,0 +1,197 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UnknownOptionError the option is not registered in the called command (or root)
+type UnknownOptionError struct {
+	Token string     // The offending argument (i.e. "--output", "-x")
+	Name  string     // The option's name (i.e. "output", "x")
+	Path  []*Command // The called commands
+	// Similar options of the called command (or root), i.e. "--output" for "--otuput"
+	Suggestions []string
+}
+
+// MissingValueError the option expects a value, but none has been given
+type MissingValueError struct {
+	Token  string     // The offending argument (i.e. "--output", "-o")
+	Option *Option    // The option expecting a value
+	Path   []*Command // The called commands
+}
+
+// InvalidValueError the value has been refused by the option's (or argument's) Value.Set
+type InvalidValueError struct {
+	Token  string     // The offending argument or environment variable (i.e. "--num=three", "three", "MYTOOL_NUM")
+	Value  string     // The refused value (i.e. "three")
+	Option *Option    // The option the value was meant for (nil for positional arguments)
+	Arg    *Arg       // The positional argument the value was meant for (nil for options)
+	Path   []*Command // The called commands
+	Err    error      // The error returned by Value.Set
+}
+
+// UnknownCommandError the argument is neither a command nor an option, and
+// the called command (or root) does not accept positional arguments
+type UnknownCommandError struct {
+	Token       string     // The offending argument
+	Path        []*Command // The called commands
+	Suggestions []string   // Similar sub-commands of the called command (or root)
+}
+
+// UnexpectedArgumentError the positional argument exceeds the ones accepted
+// by the called command (or root)
+type UnexpectedArgumentError struct {
+	Token string     // The offending argument
+	Path  []*Command // The called commands
+}
+
+// MissingRequiredError required options and/or positional arguments have
+// not been given (neither as arguments, nor via environment or configuration)
+type MissingRequiredError struct {
+	Options []*Option  // The missing options, in definition order
+	Args    []*Arg     // The missing positional arguments
+	Path    []*Command // The called commands
+}
+
+// MutuallyExclusiveError more than one option of a mutually exclusive group has been given
+type MutuallyExclusiveError struct {
+	Options []*Option  // The given options of the group
+	Path    []*Command // The called commands
+}
+
+// RequiredTogetherError only some of the options required together have been given
+type RequiredTogetherError struct {
+	Options []*Option  // The given options of the group
+	Missing []*Option  // The missing options of the group
+	Path    []*Command // The called commands
+}
+
+// OneRequiredError none of the options of a group requiring at least one of them has been given
+type OneRequiredError struct {
+	Options []*Option  // The options of the group
+	Path    []*Command // The called commands
+}
+
+// ValidationError the option's value has been refused by one of its validators
+type ValidationError struct {
+	Option *Option    // The offending option
+	Value  string     // The refused value (i.e. "0")
+	Path   []*Command // The called commands
+	Err    error      // The error returned by the validator
+}
+
+// ValidationErrors all the errors returned by the options' and commands' validators
+type ValidationErrors struct {
+	Errors []error    // The validators' errors (*ValidationError for the options' ones)
+	Path   []*Command // The called commands
+}
+
+func (err *UnknownOptionError) Error() string {
+	return fmt.Sprintf(`unknown option "%s"%s`, err.Token, pathSuffix(err.Path))
+}
+
+func (err *MissingValueError) Error() string {
+	return fmt.Sprintf(`option "%s" expects a value%s`, err.Token, pathSuffix(err.Path))
+}
+
+func (err *InvalidValueError) Error() string {
+	target := ""
+
+	if err.Option != nil {
+		target = fmt.Sprintf(`option "%s"`, optionName(err.Option))
+	} else if err.Arg != nil {
+		target = fmt.Sprintf("argument %s", err.Arg.Name)
+	}
+
+	return fmt.Sprintf(`invalid value "%s" for %s%s: %s`, err.Value, target, pathSuffix(err.Path), err.Err)
+}
+
+// Unwrap the error returned by Value.Set
+func (err *InvalidValueError) Unwrap() error {
+	return err.Err
+}
+
+func (err *UnknownCommandError) Error() string {
+	return fmt.Sprintf(`"%s" is not a registered command nor an option%s`, err.Token, pathSuffix(err.Path))
+}
+
+func (err *UnexpectedArgumentError) Error() string {
+	return fmt.Sprintf(`unexpected argument "%s"%s`, err.Token, pathSuffix(err.Path))
+}
+
+func (err *MissingRequiredError) Error() string {
+	missing := []string{}
+
+	for _, option := range err.Options {
+		missing = append(missing, fmt.Sprintf(`option "%s"`, optionName(option)))
+	}
+
+	for _, arg := range err.Args {
+		missing = append(missing, fmt.Sprintf("argument %s", arg.Name))
+	}
+
+	return fmt.Sprintf("missing required %s%s", strings.Join(missing, ", "), pathSuffix(err.Path))
+}
+
+func (err *MutuallyExclusiveError) Error() string {
+	return fmt.Sprintf("options %s are mutually exclusive%s", optionNames(err.Options), pathSuffix(err.Path))
+}
+
+func (err *RequiredTogetherError) Error() string {
+	return fmt.Sprintf(
+		"options %s require %s%s", optionNames(err.Options), optionNames(err.Missing), pathSuffix(err.Path),
+	)
+}
+
+func (err *OneRequiredError) Error() string {
+	return fmt.Sprintf("one of the options %s is required%s", optionNames(err.Options), pathSuffix(err.Path))
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf(`invalid value "%s" for option "%s": %s`, err.Value, optionName(err.Option), err.Err)
+}
+
+// Unwrap the error returned by the validator
+func (err *ValidationError) Unwrap() error {
+	return err.Err
+}
+
+func (err *ValidationErrors) Error() string {
+	messages := make([]string, 0, len(err.Errors))
+	for _, validationErr := range err.Errors {
+		messages = append(messages, validationErr.Error())
+	}
+
+	return fmt.Sprintf("validation failed%s: %s", pathSuffix(err.Path), strings.Join(messages, "; "))
+}
+
+// pathSuffix the called commands' representation in the errors (i.e. ` (command "remote add")`)
+func pathSuffix(path []*Command) string {
+	if len(path) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(path))
+	for _, cmd := range path {
+		names = append(names, cmd.Name)
+	}
+
+	return fmt.Sprintf(` (command "%s")`, strings.Join(names, " "))
+}
+
+// errorSuggestions the suggestions of unknown commands' and options' errors
+func errorSuggestions(err error) []string {
+	var optionErr *UnknownOptionError
+	if errors.As(err, &optionErr) {
+		return optionErr.Suggestions
+	}
+
+	var commandErr *UnknownCommandError
+	if errors.As(err, &commandErr) {
+		return commandErr.Suggestions
+	}
+
+	return []string{}
+}
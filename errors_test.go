@@ -0,0 +1,196 @@
+package flags
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownOptionError(t *testing.T) {
+	cmd := &Command{Name: "build"}
+	cmd.WithOptions(NewBool("", 'a', "", false))
+
+	flags := Flags{}
+	flags.WithCommands(cmd)
+
+	for _, args := range [][]string{{"build", "--unknown"}, {"build", "-ax"}} {
+		_, err := flags.ParseArgs(args)
+
+		var optErr *UnknownOptionError
+
+		assert.True(t, errors.As(err, &optErr))
+		assert.Equal(t, []*Command{cmd}, optErr.Path)
+	}
+
+	_, err := flags.ParseArgs([]string{"build", "--unknown=value"})
+
+	var optErr *UnknownOptionError
+
+	assert.True(t, errors.As(err, &optErr))
+	assert.Equal(t, "--unknown", optErr.Token)
+	assert.Equal(t, "unknown", optErr.Name)
+	assert.Equal(t, `unknown option "--unknown" (command "build")`, err.Error())
+}
+
+func TestMissingValueError(t *testing.T) {
+	opt := NewString("out", 'o', "", "")
+
+	flags := Flags{}
+	flags.WithOptions(opt)
+
+	for _, token := range []string{"--out", "-o"} {
+		_, err := flags.ParseArgs([]string{token})
+
+		var valueErr *MissingValueError
+
+		assert.True(t, errors.As(err, &valueErr))
+		assert.Equal(t, token, valueErr.Token)
+		assert.Equal(t, opt, valueErr.Option)
+		assert.Empty(t, valueErr.Path)
+		assert.Equal(t, `option "`+token+`" expects a value`, err.Error())
+	}
+}
+
+func TestInvalidValueError(t *testing.T) {
+	opt := NewInt("num", 'n', "", 0)
+	boolOpt := NewBool("bool", 'b', "", false)
+	arg := &Arg{Name: "COUNT", Value: &Int{}}
+
+	flags := Flags{}
+	flags.WithOptions(opt, boolOpt)
+	flags.WithArgs(arg)
+
+	_, err := flags.ParseArgs([]string{"--num=three"})
+
+	var valueErr *InvalidValueError
+
+	assert.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, "--num=three", valueErr.Token)
+	assert.Equal(t, "three", valueErr.Value)
+	assert.Equal(t, opt, valueErr.Option)
+
+	var numErr *strconv.NumError
+
+	assert.True(t, errors.As(err, &numErr))
+
+	_, err = flags.ParseArgs([]string{"--bool=maybe"})
+	assert.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, boolOpt, valueErr.Option)
+
+	_, err = flags.ParseArgs([]string{"three"})
+	assert.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, arg, valueErr.Arg)
+	assert.Nil(t, valueErr.Option)
+	assert.Contains(t, err.Error(), `invalid value "three" for argument COUNT`)
+}
+
+func TestUnknownCommandError(t *testing.T) {
+	remote := &Command{Name: "remote"}
+
+	flags := Flags{}
+	flags.WithCommands(remote)
+
+	_, err := flags.ParseArgs([]string{"remote", "ad"})
+
+	var cmdErr *UnknownCommandError
+
+	assert.True(t, errors.As(err, &cmdErr))
+	assert.Equal(t, "ad", cmdErr.Token)
+	assert.Equal(t, []*Command{remote}, cmdErr.Path)
+	assert.Equal(t, `"ad" is not a registered command nor an option (command "remote")`, err.Error())
+}
+
+func TestArgumentErrors(t *testing.T) {
+	src := &Arg{Name: "SRC", Value: &String{}, Required: true}
+
+	flags := Flags{}
+	flags.WithArgs(src)
+
+	_, err := flags.ParseArgs([]string{"a", "b"})
+
+	var unexpectedErr *UnexpectedArgumentError
+
+	assert.True(t, errors.As(err, &unexpectedErr))
+	assert.Equal(t, "b", unexpectedErr.Token)
+}
+
+func TestMissingRequiredError(t *testing.T) {
+	name := NewString("name", 'n', "", "")
+	name.Required = true
+	token := NewString("token", EmptyShort, "", "")
+	token.Required = true
+	token.EnvVar = "TOKEN"
+	verbose := NewBool("", 'v', "", false)
+	verbose.Required = true
+	src := &Arg{Name: "SRC", Value: &String{}, Required: true}
+	dst := &Arg{Name: "DST", Value: &String{}, Required: true}
+	copyCmd := &Command{Name: "copy"}
+	copyCmd.WithOptions(verbose)
+	copyCmd.WithArgs(src, dst)
+
+	flags := Flags{}
+	flags.WithOptions(name, token)
+	flags.WithCommands(copyCmd)
+	flags.LookupEnv = testLookupEnv(map[string]string{})
+
+	_, err := flags.ParseArgs([]string{"copy"})
+
+	var missingErr *MissingRequiredError
+
+	assert.True(t, errors.As(err, &missingErr))
+	assert.Equal(t, []*Option{name, token, verbose}, missingErr.Options)
+	assert.Equal(t, []*Arg{src, dst}, missingErr.Args)
+	assert.Equal(t, []*Command{copyCmd}, missingErr.Path)
+	assert.Equal(
+		t,
+		`missing required option "--name", option "--token", option "-v", argument SRC, argument DST (command "copy")`,
+		err.Error(),
+	)
+
+	// explicit empty values and environment variables satisfy the requirement
+	flags.LookupEnv = testLookupEnv(map[string]string{"TOKEN": "secret"})
+	_, err = flags.ParseArgs([]string{"--name=", "copy", "-v", "a"})
+
+	assert.True(t, errors.As(err, &missingErr))
+	assert.Empty(t, missingErr.Options)
+	assert.Equal(t, []*Arg{dst}, missingErr.Args)
+
+	_, err = flags.ParseArgs([]string{"--name=", "copy", "-v", "a", "b"})
+	assert.NoError(t, err)
+}
+
+func TestErrorsSuggestions(t *testing.T) {
+	verbose := NewBool("verbose", 'v', "", false)
+	build := &Command{Name: "build"}
+
+	flags := Flags{}
+	flags.WithOptions(verbose)
+	flags.WithCommands(build, &Command{Name: "test"})
+
+	_, err := flags.ParseArgs([]string{"biuld"})
+
+	var cmdErr *UnknownCommandError
+
+	assert.True(t, errors.As(err, &cmdErr))
+	assert.Equal(t, []string{"build"}, cmdErr.Suggestions)
+
+	_, err = flags.ParseArgs([]string{"--verbos"})
+
+	var optErr *UnknownOptionError
+
+	assert.True(t, errors.As(err, &optErr))
+	assert.Equal(t, []string{"--verbose"}, optErr.Suggestions)
+
+	flags.SuggestionsDistance = 1
+	_, err = flags.ParseArgs([]string{"biuld"})
+	assert.True(t, errors.As(err, &cmdErr))
+	assert.Empty(t, cmdErr.Suggestions)
+
+	flags.SuggestionsDistance = 0
+	flags.DisableSuggestions = true
+	_, err = flags.ParseArgs([]string{"--verbos"})
+	assert.True(t, errors.As(err, &optErr))
+	assert.Empty(t, optErr.Suggestions)
+}
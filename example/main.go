@@ -36,18 +36,15 @@ func main() {
 	optFlags.WithCommands(buildCmd, testCmd)
 
 	// parse the command's arguments
-	err := optFlags.Parse(true)
-	// This happens only if flags are mis-configured
-	if err != nil {
-		panic(err)
-	}
+	// print the help (--help) or the error and exit, if needed
+	result := optFlags.MustParse()
 
 	// 2nd value is error, only if the option is not of type Bool
-	if isDebug, _ := flags.BoolValue(debugOpt); isDebug {
+	if isDebug, _ := flags.BoolValue(result.Option(debugOpt)); isDebug {
 		fmt.Println("Debug mode on")
 	}
 
-	if zFactor, _ := flags.BoolValue(zOpt); zFactor {
+	if zFactor, _ := flags.BoolValue(result.Option(zOpt)); zFactor {
 		fmt.Println("Z factor. Party hard.")
 	}
 }
@@ -1,49 +1,236 @@
 package flags
 
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+)
+
 // Value all values must adhere to this interface
 type Value interface {
 	String() string             // String representation of the value
 	DefaultValueString() string // String representation of the default value
 	// Set - Used to set from the application's arguments.
-	// If RequiresValue() == true, ("true" or "") or "false" are expected
+	// If IsBoolValue() == true, "" (the option is given), "true" or "false" are expected
 	Set(string) error
 	IsBoolValue() bool // If it returns true and no parameter is specified, Set will be called with "true"
 }
 
+// ChoicesValue values restricted to a set of choices, listed in the help
+// and exposed for the shell completion (see Choices)
+type ChoicesValue interface {
+	Value
+	ValueChoices() []string // The valid values
+}
+
+// CumulativeValue values accumulating the repeated occurrences of the option
+// (i.e. -I dir1 -I dir2). The first set replaces the default values
+type CumulativeValue interface {
+	Value
+	IsCumulative() bool
+	DefaultValueStrings() []string // String representations of the default values, listed in the help
+}
+
 // Option Application or command level option
 type Option struct {
-	Short       rune   // Short option name (i.e. 'd')
-	Long        string // Long option name (i.e. "debug")
-	Description string // Option's description (i.e. "Log debug messages")
-	Value       Value  // Option's value and default value
+	Short       rune        // Short option name (i.e. 'd')
+	Long        string      // Long option name (i.e. "debug")
+	Description string      // Option's description (i.e. "Log debug messages")
+	Value       Value       // Option's value and default value
+	Persistent  bool        // The option is accepted by all the sub-commands, too
+	EnvVar      string      // Environment variable, used if the option is not given (i.e. "MYTOOL_DEBUG")
+	Required    bool        // The option must be given (as argument, environment variable or in the configuration file)
+	Validators  []Validator // Eventual checks of the given value (i.e. Min(1), NonEmpty())
 }
 
+// Arg Application or command level positional argument
+type Arg struct {
+	Name        string // Argument's name (i.e. "SRC")
+	Description string // Argument's description (i.e. "Source file")
+	Value       Value  // Argument's value and default value
+	Required    bool   // The argument must be given by the user
+	Variadic    bool   // The argument collects all the remaining positionals (last argument only)
+	values      []string
+}
+
+// Validator checks an option's value once resolved (from any source)
+type Validator func(value Value) error
+
+// CommandValidator checks the options' values of the called commands as a whole
+// (i.e. --min lower than --max), once all of them are resolved
+type CommandValidator func(result *ParseResult) error
+
+// GroupKind the kind of constraint of an options' group
+type GroupKind int
+
+// OptionGroup a constraint on a group of options, evaluated after the
+// parse (the options are considered given if set by any source)
+type OptionGroup struct {
+	Kind    GroupKind // The kind of constraint
+	Options []*Option // The constrained options
+}
+
+// Action the function run by Execute for the called command (or the root)
+type Action func(ctx context.Context, result *ParseResult) error
+
 // Command a command, or subcommand, called by the user
 type Command struct {
-	Name        string     // Name of the command
-	Description string     // Description of the command
-	Options     []*Option  // Eventual options bound to the command
-	SubCommands []*Command // Eventual sub-commands
-	Called      bool
+	Name        string             // Name of the command
+	Description string             // Description of the command
+	Options     []*Option          // Eventual options bound to the command
+	Args        []*Arg             // Eventual positional arguments bound to the command
+	Groups      []*OptionGroup     // Eventual constraints on the command's options
+	Validators  []CommandValidator // Eventual cross-option checks, run once all the values are resolved
+	Passthrough bool               // Collect the arguments following "--" verbatim (see ParseResult.Rest)
+	SubCommands []*Command         // Eventual sub-commands
+
+	Run               Action // Eventual action, run by Flags.Execute if the command is the deepest called one
+	PreRun            Action // Eventual hook, run before Run
+	PostRun           Action // Eventual hook, run after Run
+	PersistentPreRun  Action // Eventual hook, run before the PreRun of the command and of its sub-commands
+	PersistentPostRun Action // Eventual hook, run after the PostRun of the command and of its sub-commands
 }
 
 // Flags main struct for setting up commands and options
 type Flags struct {
-	AppName        string     // application's name
-	AppVersion     string     // application's version
-	AppDescription string     // application's description
-	Options        []*Option  // application-level options
-	Commands       []*Command // available commands
-	currentCommand *Command
+	AppName        string             // application's name
+	AppVersion     string             // application's version
+	AppDescription string             // application's description
+	Options        []*Option          // application-level options
+	Args           []*Arg             // application-level positional arguments
+	Groups         []*OptionGroup     // application-level options' constraints
+	Validators     []CommandValidator // application-level cross-option checks
+	Passthrough    bool               // collect the arguments following "--" verbatim (see ParseResult.Rest)
+	Commands       []*Command         // available commands
+
+	Run               Action // eventual action, run by Execute if no command is called
+	PreRun            Action // eventual hook, run before Run
+	PostRun           Action // eventual hook, run after Run
+	PersistentPreRun  Action // eventual hook, run before any other hook and action
+	PersistentPostRun Action // eventual hook, run after any other hook and action
+
+	// prefix of the options' automatic environment variables (i.e. "MYTOOL" for MYTOOL_BUILD_DRY_RUN)
+	EnvPrefix string
+	// environment variables' lookup function (os.LookupEnv if nil)
+	LookupEnv func(key string) (string, bool)
+
+	// option holding the configuration file's path (i.e. --config), applied
+	// to the options given neither as arguments nor as environment variables
+	ConfigOption *Option
+	// configuration files' decoders by extension (i.e. ".yaml"), in addition to
+	// the built-in JSONDecoder (".json") and INIDecoder (".ini")
+	ConfigDecoders map[string]ConfigDecoder
+
+	Stdout   io.Writer // help's output (os.Stdout if nil)
+	Stderr   io.Writer // errors' output (os.Stderr if nil)
+	ExitCode int       // MustParse's exit code on error (DefaultExitCode if 0)
+
+	SuggestionsDistance int  // maximum edit distance of the suggestions (DefaultSuggestionsDistance if 0)
+	DisableSuggestions  bool // do not suggest commands and options on unknown ones
+}
+
+// ParseResult the outcome of a parse: the called commands, the options' and
+// arguments' values and the arguments following "--". The definitions
+// (Flags, Command, Option, Arg) are never modified by the parse
+type ParseResult struct {
+	path      []*Command         // called commands, from the root's one to the deepest
+	values    map[*Option]Value  // options' values (copies of the definitions' ones)
+	sources   map[*Option]Source // options' values sources
+	argValues map[*Arg]Value     // positional arguments' values
+	argRaw    map[*Arg][]string  // positional arguments' raw values
+	rest      []string           // arguments following "--"
 }
 
+// ConfigDecoder decode a configuration file in a tree of values. The keys are
+// either the options' long names or the commands' names (sections holding the
+// command's options and sub-commands). Values are strings, numbers, booleans
+// or lists of them (set one by one)
+type ConfigDecoder interface {
+	Decode(reader io.Reader) (map[string]interface{}, error)
+}
+
+// ConfigDecoderFunc adapter to use ordinary functions as ConfigDecoder
+type ConfigDecoderFunc func(reader io.Reader) (map[string]interface{}, error)
+
+// SourceKind the kind of source of an option's value
+type SourceKind int
+
+// Source the source of an option's value
+type Source struct {
+	Kind     SourceKind // The kind of source
+	ArgIndex int        // The argument's index (SourceArgs)
+	Arg      string     // The argument (SourceArgs, i.e. "--num=3")
+	EnvVar   string     // The environment variable (SourceEnv)
+	File     string     // The configuration file's path (SourceConfig)
+	Key      string     // The configuration file's key, dot-separated (SourceConfig, i.e. "build.dry-run")
+}
+
+// StandaloneBoolValue bool values never taking their value from the next argument,
+// which is kept as positional (i.e. Counter: -vvv 1). The other bool values take it
+// if it is a true or false literal (i.e. --debug false)
+type StandaloneBoolValue interface {
+	Value
+	IsStandalone() bool
+}
+
+// Cloner values holding references (i.e. maps or pointers) should implement it,
+// so that every parse works on its own copy. Other values are shallow-copied
+type Cloner interface {
+	Clone() Value
+}
+
+// Sources of the options' values
+const (
+	SourceDefault SourceKind = iota // Option's default value
+	SourceArgs                      // Application's arguments
+	SourceEnv                       // Environment variable
+	SourceConfig                    // Configuration file
+)
+
+// Options' groups constraints
+const (
+	GroupMutuallyExclusive GroupKind = iota // At most one of the options can be given
+	GroupRequiredTogether                   // Either all the options or none of them must be given
+	GroupOneRequired                        // At least one of the options must be given
+)
+
+// negationPrefix the prefix of the negated bool options (i.e. --no-color)
+const negationPrefix = "no-"
+
+// DefaultExitCode MustParse's exit code on error, if Flags.ExitCode is not set
+const DefaultExitCode = 1
+
+// DefaultSuggestionsDistance maximum edit distance of the suggestions, if Flags.SuggestionsDistance is not set
+const DefaultSuggestionsDistance = 2
+
+// ErrHelp returned by the parse when the help option is given
+var ErrHelp = errors.New("help requested")
+
+// ErrPrintConfig returned by the parse when the print configuration option is given
+var ErrPrintConfig = errors.New("configuration print requested")
+
+// osExit MustParse's exit function, replaceable in tests
+var osExit = os.Exit
+
 // EmptyShort the short option name's null-value
 var EmptyShort rune
 
-// HelpOption add it to the root to enable the automatic help prompt
+// HelpOption add it to the root to enable the automatic help prompt.
+// Being never modified by the parse, it can be shared between Flags instances
 var HelpOption = &Option{
 	Short:       'h',
 	Long:        "help",
 	Description: "Show the application's help",
 	Value:       &Bool{},
+	Persistent:  true,
+}
+
+// PrintConfigOption add it to the root to enable the automatic print of the
+// effective options' values, along with their sources, for the called commands
+var PrintConfigOption = &Option{
+	Long:        "print-config",
+	Description: "Show the effective options' values and their sources",
+	Value:       &Bool{},
+	Persistent:  true,
 }
@@ -1,10 +1,10 @@
 package flags
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
-	"regexp"
 	"strings"
 	"text/tabwriter"
 )
@@ -24,234 +24,343 @@ func (flags *Flags) WithCommands(cmds ...*Command) {
 	flags.Commands = append(flags.Commands, cmds...)
 }
 
-// WithOptions add one or more options to the main help object
+// WithOptions add one or more options to the main help object.
+// It panics if an option's long or short name or an enum's default value is not valid
 func (flags *Flags) WithOptions(opts ...*Option) {
 	if flags.Options == nil {
 		flags.Options = []*Option{}
 	}
 
+	for _, opt := range opts {
+		if err := validateOption(opt); err != nil {
+			panic(err)
+		}
+	}
+
 	flags.Options = append(flags.Options, opts...)
 }
 
-// GetCalledCommand Get the called command, if any
-func (flags *Flags) GetCalledCommand() *Command {
-	for _, cmd := range flags.Commands {
-		if cmd.Called {
-			return cmd
+// WithArgs add one or more positional arguments to the main help object.
+// It panics if the arguments' definition is not valid
+// (i.e. a variadic argument which is not the last one)
+func (flags *Flags) WithArgs(args ...*Arg) {
+	if flags.Args == nil {
+		flags.Args = []*Arg{}
+	}
+
+	if err := validateArgs(append(flags.Args, args...)); err != nil {
+		panic(err)
+	}
+
+	flags.Args = append(flags.Args, args...)
+}
+
+// WithGroups add one or more options' constraints to the main help object.
+// It panics if a group has less than two options
+func (flags *Flags) WithGroups(groups ...*OptionGroup) {
+	if flags.Groups == nil {
+		flags.Groups = []*OptionGroup{}
+	}
+
+	for _, group := range groups {
+		if err := validateGroup(group); err != nil {
+			panic(err)
 		}
 	}
 
-	return nil
+	flags.Groups = append(flags.Groups, groups...)
 }
 
 // Parse parse the application's arguments
-func (flags *Flags) Parse(printHelpOnError bool) error {
-	return flags.ParseArgs(os.Args[1:], printHelpOnError) // first element is the app's name
+func (flags *Flags) Parse() (*ParseResult, error) {
+	return flags.ParseArgs(os.Args[1:]) // first element is the app's name
 }
 
-// ParseArgs parse arbitrary arguments
-func (flags *Flags) ParseArgs(args []string, printHelpOnError bool) error {
-	// guard condition
-	if len(args) == 0 {
-		// Print the help if the option is triggered
-		if HelpOption.Value.(*Bool).Value {
-			flags.PrintHelp()
-		}
+// ParseArgs parse arbitrary arguments. The definitions are not modified,
+// so the same Flags can be parsed multiple times (even concurrently).
+// If the help option is given, ErrHelp is returned (along with the result);
+// if the print configuration option is given, ErrPrintConfig is returned
+// once the environment variables and the configuration file are applied
+func (flags *Flags) ParseArgs(args []string) (*ParseResult, error) {
+	p := newParser(flags)
 
-		return nil
+	if err := p.parse(args); err != nil {
+		return p.result, err
 	}
 
-	commands := flags.Commands
-	options := flags.Options
+	if help, _ := BoolValue(p.result.Option(HelpOption)); help {
+		return p.result, ErrHelp
+	}
 
-	if flags.currentCommand != nil {
-		commands = flags.currentCommand.SubCommands
-		options = flags.currentCommand.Options
+	if err := p.applyEnv(); err != nil {
+		return p.result, err
 	}
 
-	arg := args[0]
-	argConsumed := false
-	nextArg := ""
-	nextArgConsumed := false
+	if err := p.applyConfig(); err != nil {
+		return p.result, err
+	}
 
-	if len(args) > 1 {
-		nextArg = args[1]
+	if printConfig, _ := BoolValue(p.result.Option(PrintConfigOption)); printConfig {
+		return p.result, ErrPrintConfig
 	}
 
-	if isOption(arg) {
-		trueFalseRegexp := regexp.MustCompile("(?i)(true|false)")
+	if err := p.bindArgs(); err != nil {
+		return p.result, err
+	}
 
-		if isShortOption(arg) {
-			// possibly multiple flags, i.e. -abc
-			for i := 1; i < len(arg); i++ {
-				subArg := rune(arg[i])
-				canAccessNextArg := i == (len(arg) - 1)
+	if err := p.checkRequired(); err != nil {
+		return p.result, err
+	}
 
-				for _, option := range options {
-					if option.Short == subArg {
-						if option.Value.IsBoolValue() {
-							if canAccessNextArg && trueFalseRegexp.MatchString(nextArg) {
-								err := option.Value.Set(strings.ToLower(nextArg))
-								if err != nil {
-									return err
-								}
+	if err := p.checkGroups(); err != nil {
+		return p.result, err
+	}
 
-								nextArgConsumed = true
-								argConsumed = true
+	return p.result, p.validate()
+}
 
-								break
-							}
+// MustParse parse the application's arguments (see MustParseArgs)
+func (flags *Flags) MustParse() *ParseResult {
+	return flags.MustParseArgs(os.Args[1:]) // first element is the app's name
+}
 
-							if err := option.Value.Set("true"); err != nil {
-								return err
-							}
+// MustParseArgs parse arbitrary arguments. If the help option is given,
+// the help is printed to Stdout and the application exits with code 0
+// (the same for the print configuration option); on error, the error and
+// the help are printed to Stderr and the application exits with ExitCode
+func (flags *Flags) MustParseArgs(args []string) *ParseResult {
+	result, err := flags.ParseArgs(args)
+
+	if err == ErrHelp {
+		flags.printHelpForPath(result.Path(), flags.stdout())
+		osExit(0)
+	} else if err == ErrPrintConfig {
+		flags.PrintConfig(result, flags.stdout())
+		osExit(0)
+	} else if err != nil {
+		flags.printError(err, result.Path())
+		osExit(flags.exitCode())
+	}
 
-							argConsumed = true
+	return result
+}
 
-							break
-						}
+// Execute parse the arguments and run the action of the deepest called
+// command (or the root's one, if no command is called), returning its error.
+// The hooks are run in the following order, stopping at the first error:
+// PersistentPreRun (from the root to the deepest command), PreRun, Run,
+// PostRun, PersistentPostRun (from the deepest command to the root).
+// If the help option is given, or the command has no action, the help is
+// printed to Stdout instead (the effective configuration, if the print
+// configuration option is given)
+func (flags *Flags) Execute(ctx context.Context, args []string) error {
+	result, err := flags.ParseArgs(args)
+	if err == ErrPrintConfig {
+		flags.PrintConfig(result, flags.stdout())
 
-						if !canAccessNextArg || nextArg == "" {
-							if printHelpOnError {
-								flags.PrintHelpWithArgs([]string{}, os.Stdout)
-								os.Exit(1)
-							}
+		return nil
+	} else if err != nil && err != ErrHelp {
+		return err
+	}
 
-							return fmt.Errorf("Option '%c' expects a value", subArg)
-						}
+	path := result.Path()
+	action, preRun, postRun := flags.Run, flags.PreRun, flags.PostRun
+	persistentPreRuns := []Action{flags.PersistentPreRun}
+	persistentPostRuns := []Action{flags.PersistentPostRun}
 
-						nextArgConsumed = true
-						err := option.Value.Set(nextArg)
+	if cmd := result.Command(); cmd != nil {
+		action, preRun, postRun = cmd.Run, cmd.PreRun, cmd.PostRun
+	}
 
-						if err != nil {
-							return err
-						}
+	for _, cmd := range path {
+		persistentPreRuns = append(persistentPreRuns, cmd.PersistentPreRun)
+		persistentPostRuns = append([]Action{cmd.PersistentPostRun}, persistentPostRuns...)
+	}
 
-						argConsumed = true
+	if err == ErrHelp || action == nil {
+		flags.printHelpForPath(path, flags.stdout())
 
-						break
-					}
-				}
-			}
+		return nil
+	}
 
-			if argConsumed {
-				if nextArgConsumed {
-					return flags.ParseArgs(args[2:], printHelpOnError)
-				}
+	chain := append(persistentPreRuns, preRun, action, postRun)
+	chain = append(chain, persistentPostRuns...)
 
-				return flags.ParseArgs(args[1:], printHelpOnError)
-			}
+	for _, run := range chain {
+		if run == nil {
+			continue
 		}
 
-		// Long option
-		argName, err := getOptionName(arg)
-		if err != nil {
-			if printHelpOnError {
-				flags.PrintHelpWithArgs([]string{}, os.Stdout)
-				os.Exit(1)
-			}
-
+		if err := run(ctx, result); err != nil {
 			return err
 		}
+	}
 
-		for _, option := range options {
-			if option.Long == argName {
-				if option.Value.IsBoolValue() {
-					if trueFalseRegexp.MatchString(nextArg) {
-						if err := option.Value.Set(strings.ToLower(nextArg)); err != nil {
-							return err
-						}
+	return nil
+}
 
-						nextArgConsumed = true
-						argConsumed = true
+// Find the command at the given path (i.e. "remote", "add"), nil if not found
+func (flags *Flags) Find(names ...string) *Command {
+	path := flags.FindPath(names...)
+	if len(path) == 0 {
+		return nil
+	}
 
-						break
-					}
+	return path[len(path)-1]
+}
 
-					if err := option.Value.Set("true"); err != nil {
-						return err
-					}
+// FindPath the commands at the given path (i.e. "remote", "add"), from the
+// root's one to the deepest. It returns nil if any of the commands is not found
+func (flags *Flags) FindPath(names ...string) []*Command {
+	path := []*Command{}
 
-					argConsumed = true
+	var parent *Command
 
-					break
-				}
+	for _, name := range names {
+		command := findCommand(flags.subCommands(parent), name)
+		if command == nil {
+			return nil
+		}
 
-				if nextArg == "" {
-					if printHelpOnError {
-						flags.PrintHelpWithArgs([]string{}, os.Stdout)
-						os.Exit(1)
-					}
+		path = append(path, command)
+		parent = command
+	}
+
+	return path
+}
 
-					return fmt.Errorf("Option '%s' expects a value", arg)
-				}
+// subCommands the sub-commands of the command, or the root's commands if nil
+func (flags *Flags) subCommands(cmd *Command) []*Command {
+	if cmd != nil {
+		return cmd.SubCommands
+	}
 
-				if err := option.Value.Set(nextArg); err != nil {
-					return err
-				}
+	return flags.Commands
+}
 
-				nextArgConsumed = true
-				argConsumed = true
+// persistentOptions the persistent options inherited by the deepest command
+// of the path, from the nearest parent's ones to the root's ones
+func (flags *Flags) persistentOptions(path []*Command) []*Option {
+	options := []*Option{}
 
-				break
-			}
+	for i := len(path) - 2; i >= -1; i-- {
+		levelOptions := flags.Options
+		if i >= 0 {
+			levelOptions = path[i].Options
 		}
 
-		if argConsumed {
-			if nextArgConsumed {
-				return flags.ParseArgs(args[2:], printHelpOnError)
+		for _, option := range levelOptions {
+			if option.Persistent {
+				options = append(options, option)
 			}
-
-			return flags.ParseArgs(args[1:], printHelpOnError)
 		}
 	}
 
-	// Not an Option, so it's a Command
-	for _, command := range commands {
-		if command.Name == arg {
-			command.Called = true
-			flags.currentCommand = command
+	return options
+}
+
+// PrintHelp print the help information to Stdout
+func (flags *Flags) PrintHelp() {
+	flags.PrintHelpWithArgs(os.Args, flags.stdout())
+}
+
+// PrintConfig print the effective options' values of the called commands,
+// along with their sources (i.e. --num  "3"  env NUM)
+func (flags *Flags) PrintConfig(result *ParseResult, output io.Writer) {
+	chain := []string{flags.AppName}
+	options := append([]*Option{}, flags.Options...)
+
+	for _, cmd := range result.Path() {
+		chain = append(chain, cmd.Name)
+		options = append(options, cmd.Options...)
+	}
+
+	fmt.Fprintln(output, "Effective configuration for: "+strings.TrimLeft(strings.Join(chain, " "), " "))
+	fmt.Fprintln(output, "")
+
+	tabWriter := tabwriter.NewWriter(output, 7, 8, 7, '\t', 0)
 
-			return flags.ParseArgs(args[1:], printHelpOnError)
+	for _, opt := range options {
+		if opt == HelpOption || opt == PrintConfigOption {
+			continue
 		}
+
+		fmt.Fprintf(tabWriter, "%s\t%q\t%s\n", optionName(opt), result.Value(opt).String(), result.Source(opt))
 	}
 
-	if printHelpOnError {
-		flags.PrintHelpWithArgs([]string{}, os.Stdout)
-		os.Exit(1)
+	tabWriter.Flush()
+}
+
+// printError print the error, followed by the help of the called commands, to Stderr
+func (flags *Flags) printError(err error, path []*Command) {
+	fmt.Fprintf(flags.stderr(), "Error: %s\n", err)
+
+	if suggestions := errorSuggestions(err); len(suggestions) > 0 {
+		fmt.Fprintf(flags.stderr(), "\nDid you mean this?\n\t%s\n", strings.Join(suggestions, "\n\t"))
 	}
 
-	return fmt.Errorf(`"%s" is not a registered command nor an option`, arg)
+	fmt.Fprintln(flags.stderr(), "")
+	flags.printHelpForPath(path, flags.stderr())
 }
 
-// PrintHelp print the help information
-func (flags *Flags) PrintHelp() {
-	flags.PrintHelpWithArgs(os.Args, os.Stdout)
+func (flags *Flags) stdout() io.Writer {
+	if flags.Stdout != nil {
+		return flags.Stdout
+	}
+
+	return os.Stdout
 }
 
-// PrintHelpWithArgs print the help information
+func (flags *Flags) stderr() io.Writer {
+	if flags.Stderr != nil {
+		return flags.Stderr
+	}
+
+	return os.Stderr
+}
+
+func (flags *Flags) exitCode() int {
+	if flags.ExitCode != 0 {
+		return flags.ExitCode
+	}
+
+	return DefaultExitCode
+}
+
+// PrintHelpWithArgs print the help information of the commands called by
+// the arguments, the first one being the application's name (i.e. os.Args).
+// The commands are resolved by walking the arguments only, even if they are not valid:
+// neither the environment variables nor the configuration file are read, nor the validators run
 func (flags *Flags) PrintHelpWithArgs(args []string, output io.Writer) {
-	var lastCommand *Command
+	if len(args) > 0 {
+		args = args[1:] // first element is the app's name
+	}
+
+	p := newParser(flags)
+	_ = p.parse(args)
+
+	flags.printHelpForPath(p.result.Path(), output)
+}
 
+// printHelpForPath print the help information of the called commands (the root's one if empty)
+func (flags *Flags) printHelpForPath(path []*Command, output io.Writer) {
 	commandChain := []string{}
 	commands := flags.Commands
 	options := flags.Options
+	positionalArgs := flags.Args
+	groups := flags.Groups
+	passthrough := flags.Passthrough
 
-	for _, arg := range args {
-		if isOption(arg) {
-			continue
-		}
-
-		for _, command := range commands {
-			if command.Name == arg {
-				commandChain = append(commandChain, command.Name)
-				lastCommand = command
-				commands = command.SubCommands
-				options = command.Options
+	var lastCommand *Command
 
-				break
-			}
-		}
+	for _, command := range path {
+		commandChain = append(commandChain, command.Name)
+		lastCommand = command
+		commands = command.SubCommands
+		options = command.Options
+		positionalArgs = command.Args
+		groups = command.Groups
+		passthrough = command.Passthrough
 	}
 
 	if flags.AppName != "" {
@@ -283,35 +392,69 @@ func (flags *Flags) PrintHelpWithArgs(args []string, output io.Writer) {
 
 	tabWriter := tabwriter.NewWriter(output, 7, 8, 7, '\t', 0)
 
-	if len(options) > 0 {
+	if len(positionalArgs) > 0 || passthrough {
+		usage := append([]string{flags.AppName}, commandChain...)
+		for _, arg := range positionalArgs {
+			usage = append(usage, arg.argUsage())
+		}
+
+		if passthrough {
+			usage = append(usage, "[-- ARGS...]")
+		}
+
+		fmt.Fprintln(output, "")
+		fmt.Fprintln(output, "Usage: "+strings.TrimLeft(strings.Join(usage, " "), " "))
+	}
+
+	if len(positionalArgs) > 0 {
 		fmt.Fprintln(output, "")
-		fmt.Fprintln(output, "Available options.")
+		fmt.Fprintln(output, "Available arguments.")
 		fmt.Fprintln(output, "")
 
-		for _, opt := range options {
-			description := opt.Description
-			defaultValue := opt.Value.DefaultValueString()
+		for _, arg := range positionalArgs {
+			description := arg.Description
 
-			if defaultValue != "" {
+			if arg.Required {
+				description += " (required)"
+			} else if defaultValue := arg.Value.DefaultValueString(); defaultValue != "" {
 				description = fmt.Sprintf(`%s (default value: "%s")`, description, defaultValue)
 			}
 
 			descriptionLines, _ := textSplit(description, 64)
-			line := ""
+			fmt.Fprintf(tabWriter, "%s\t%s\n", arg.argUsage(), strings.Join(descriptionLines, "\n\t"))
+		}
 
-			if opt.Long != "" {
-				line = fmt.Sprintf("--%s", opt.Long)
-			}
+		tabWriter.Flush()
+	}
 
-			if opt.Short != EmptyShort {
-				line = fmt.Sprintf("%s\t-%c", line, opt.Short)
-			} else {
-				line = fmt.Sprintf("%s\t", line)
-			}
+	flags.printOptions(output, tabWriter, "Available options.", options)
+
+	// Persistent options of the parents, unless shadowed by the command's ones
+	globalOptions := []*Option{}
 
-			line = fmt.Sprintf("%s\t%s\n", line, strings.Join(descriptionLines, "\n\t\t"))
+	for _, opt := range flags.persistentOptions(path) {
+		longShadowed := opt.Long == "" || findLongOption(options, opt.Long) != nil
+		shortShadowed := opt.Short == EmptyShort || findShortOption(options, opt.Short) != nil
 
-			fmt.Fprintf(tabWriter, "%s", line)
+		if !longShadowed || !shortShadowed {
+			globalOptions = append(globalOptions, opt)
+		}
+	}
+
+	flags.printOptions(output, tabWriter, "Global options.", globalOptions)
+
+	if len(groups) > 0 {
+		fmt.Fprintln(output, "")
+		fmt.Fprintln(output, "Options' constraints.")
+		fmt.Fprintln(output, "")
+
+		for _, group := range groups {
+			names := make([]string, 0, len(group.Options))
+			for _, opt := range group.Options {
+				names = append(names, optionName(opt))
+			}
+
+			fmt.Fprintf(tabWriter, "- %s\t%s\n", group.Kind, strings.Join(names, ", "))
 		}
 
 		tabWriter.Flush()
@@ -331,3 +474,64 @@ func (flags *Flags) PrintHelpWithArgs(args []string, output io.Writer) {
 		tabWriter.Flush()
 	}
 }
+
+// printOptions print an options' section of the help
+func (flags *Flags) printOptions(output io.Writer, tabWriter *tabwriter.Writer, title string, options []*Option) {
+	if len(options) == 0 {
+		return
+	}
+
+	fmt.Fprintln(output, "")
+	fmt.Fprintln(output, title)
+	fmt.Fprintln(output, "")
+
+	for _, opt := range options {
+		details := []string{}
+
+		if opt.Required {
+			details = append(details, "required")
+		}
+
+		if cumulative, ok := opt.Value.(CumulativeValue); ok && cumulative.IsCumulative() {
+			details = append(details, "repeatable")
+
+			if defaultValues := cumulative.DefaultValueStrings(); len(defaultValues) > 0 {
+				details = append(details, fmt.Sprintf(`default value: ["%s"]`, strings.Join(defaultValues, `", "`)))
+			}
+		} else if defaultValue := opt.Value.DefaultValueString(); defaultValue != "" {
+			details = append(details, fmt.Sprintf(`default value: "%s"`, defaultValue))
+		}
+
+		if choices := Choices(opt); len(choices) > 0 {
+			details = append(details, "choices: "+strings.Join(choices, ", "))
+		}
+
+		if envVar := flags.envVar(opt); envVar != "" {
+			details = append(details, "env: "+envVar)
+		}
+
+		description := opt.Description
+		if len(details) > 0 {
+			description = fmt.Sprintf("%s (%s)", description, strings.Join(details, ", "))
+		}
+
+		descriptionLines, _ := textSplit(description, 64)
+		line := ""
+
+		if opt.Long != "" {
+			line = fmt.Sprintf("--%s", opt.Long)
+		}
+
+		if opt.Short != EmptyShort {
+			line = fmt.Sprintf("%s\t-%c", line, opt.Short)
+		} else {
+			line = fmt.Sprintf("%s\t", line)
+		}
+
+		line = fmt.Sprintf("%s\t%s\n", line, strings.Join(descriptionLines, "\n\t\t"))
+
+		fmt.Fprintf(tabWriter, "%s", line)
+	}
+
+	tabWriter.Flush()
+}
@@ -1,7 +1,14 @@
 package flags
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -45,32 +52,124 @@ func TestFlagsWithOptions(t *testing.T) {
 	assert.Equal(t, opt2Short, flags.Options[1].Short)
 }
 
-func TestFlagsGetCalledCommand(t *testing.T) {
+func TestFlagsWithOptionsInvalidNames(t *testing.T) {
 	flags := Flags{}
-	cmdName := "build"
-	cmd := Command{Name: cmdName}
 
-	flags.WithCommands(&cmd)
-	assert.Nil(t, flags.GetCalledCommand())
+	assert.Panics(t, func() { flags.WithOptions(NewBool("dry run", EmptyShort, "", false)) })
+	assert.Panics(t, func() { flags.WithOptions(NewBool("-dry", EmptyShort, "", false)) })
+	assert.Panics(t, func() { flags.WithOptions(NewBool("", '-', "", false)) })
+	assert.NotPanics(t, func() { flags.WithOptions(NewBool("dry-run_now.please", 'd', "", false)) })
+}
+
+func TestFlagsWithOptionsInvalidEnumDefault(t *testing.T) {
+	flags := Flags{}
+
+	assert.PanicsWithError(t, `invalid default value "xml" of option --format: must be one of "json"`, func() {
+		flags.WithOptions(NewEnum("format", 'f', "", []string{"json"}, "xml"))
+	})
+	assert.Panics(t, func() { (&Command{}).WithOptions(NewEnum("", 'f', "", []string{"json"}, "JSON")) })
+	assert.NotPanics(t, func() { flags.WithOptions(NewEnum("format", 'f', "", []string{"json"}, "")) })
+	assert.NotPanics(t, func() { flags.WithOptions(NewEnum("output", 'o', "", []string{"json"}, "json")) })
+
+	caseInsensitive := NewEnum("style", 's', "", []string{"json"}, "JSON")
+	caseInsensitive.Value.(*Enum).CaseInsensitive = true
+
+	assert.NotPanics(t, func() { flags.WithOptions(caseInsensitive) })
+	assert.False(t, caseInsensitive.Value.(*Enum).ValueSet)
+}
+
+func TestFlagsFind(t *testing.T) {
+	add := &Command{Name: "add"}
+	remote := &Command{Name: "remote"}
+	remote.WithCommands(add)
+	build := &Command{Name: "build"}
+
+	flags := Flags{}
+	flags.WithCommands(build, remote)
+
+	assert.Equal(t, add, flags.Find("remote", "add"))
+	assert.Equal(t, remote, flags.Find("remote"))
+	assert.Nil(t, flags.Find("remote", "remove"))
+	assert.Nil(t, flags.Find("add"))
+	assert.Nil(t, flags.Find())
+
+	assert.Equal(t, []*Command{remote, add}, flags.FindPath("remote", "add"))
+	assert.Nil(t, flags.FindPath("build", "add"))
+	assert.Empty(t, flags.FindPath())
+}
 
-	cmd.Called = true
-	calledCmd := flags.GetCalledCommand()
-	assert.NotNil(t, calledCmd)
-	assert.Equal(t, cmdName, calledCmd.Name)
+func TestFlagsHelpPath(t *testing.T) {
+	exitCode := -1
+	osExit = func(code int) { exitCode = code }
+
+	defer func() { osExit = os.Exit }()
+
+	stdout := &testStringWriter{}
+	stderr := &testStringWriter{}
+	add := &Command{Name: "add", Description: "Add a remote"}
+	remote := &Command{Name: "remote", Description: "Manage remotes"}
+	remote.WithOptions(NewString("repo", 'r', "", ""))
+	remote.WithCommands(add)
+
+	flags := Flags{Stdout: stdout, Stderr: stderr}
+	flags.WithOptions(NewString("name", 'n', "", ""), NewBool("debug", 'd', "", false), HelpOption)
+	flags.WithCommands(remote)
+
+	// the commands are resolved by the parser: "remote" is the value of --name
+	flags.MustParseArgs([]string{"--name", "remote", "--help"})
+	assert.Equal(t, 0, exitCode)
+	assert.NotContains(t, stdout.Value, "Details for command")
+
+	stdout.Value = ""
+	flags.PrintHelpWithArgs([]string{"./app", "--debug", "remote", "-r", "add", "add"}, stdout)
+	assert.Contains(t, stdout.Value, "Details for command: remote add\n")
+
+	stdout.Value = ""
+	flags.PrintHelpWithArgs([]string{"./app", "--name", "remote"}, stdout)
+	assert.NotContains(t, stdout.Value, "Details for command")
+
+	// on error, the help of the commands called so far is printed
+	flags.MustParseArgs([]string{"remote", "--bogus", "add"})
+	assert.Equal(t, DefaultExitCode, exitCode)
+	assert.Contains(t, stderr.Value, "Details for command: remote\n")
+
+	stdout.Value = ""
+	assert.NoError(t, flags.Execute(context.Background(), []string{"--name", "remote"}))
+	assert.NotContains(t, stdout.Value, "Details for command")
+
+	// printing the help neither reads the environment nor runs the validators
+	flags.LookupEnv = func(key string) (string, bool) {
+		t.Errorf("unexpected environment lookup of %s", key)
+
+		return "", false
+	}
+	flags.Options[0].EnvVar = "NAME"
+	flags.Validators = []CommandValidator{func(result *ParseResult) error {
+		t.Error("unexpected validation")
+
+		return nil
+	}}
+
+	stdout.Value = ""
+	flags.PrintHelpWithArgs([]string{"./app", "remote"}, stdout)
+	assert.Contains(t, stdout.Value, "Details for command: remote\n")
 }
 
 func TestFlagsParseEmptyArgs(t *testing.T) {
 	flags := Flags{}
-	assert.NoError(t, flags.ParseArgs([]string{}, false))
+	result, err := flags.ParseArgs([]string{})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Path())
 }
 
 func TestFlagsParseRootOption(t *testing.T) {
 	option := &Option{Short: 't', Long: "test", Value: &Bool{}}
 	flags := Flags{}
 	flags.WithOptions(option)
-	assert.NoError(t, flags.ParseArgs([]string{"-t"}, false))
+	result, err := flags.ParseArgs([]string{"-t"})
+	assert.NoError(t, err)
 
-	val, err := BoolValue(option)
+	val, err := BoolValue(result.Option(option))
 	assert.NoError(t, err)
 	assert.True(t, val)
 }
@@ -79,8 +178,9 @@ func TestFlagsParseRootCommand(t *testing.T) {
 	cmd := &Command{Name: "cmd"}
 	flags := Flags{}
 	flags.WithCommands(cmd)
-	assert.NoError(t, flags.ParseArgs([]string{"cmd"}, false))
-	assert.True(t, cmd.Called)
+	result, err := flags.ParseArgs([]string{"cmd"})
+	assert.NoError(t, err)
+	assert.True(t, result.Called(cmd))
 }
 
 func TestFlagsParseBoolOptionWithNextArg(t *testing.T) {
@@ -88,23 +188,24 @@ func TestFlagsParseBoolOptionWithNextArg(t *testing.T) {
 	opt := NewBool("opt", 'o', "", false)
 
 	flags.WithOptions(opt)
-	assert.NoError(t, flags.ParseArgs([]string{"-o", "true"}, false))
+	result, err := flags.ParseArgs([]string{"-o", "true"})
+	assert.NoError(t, err)
 
-	val, err := BoolValue(opt)
+	val, err := BoolValue(result.Option(opt))
 	assert.NoError(t, err)
 	assert.True(t, val)
 
-	flags.WithOptions(opt)
-	assert.NoError(t, flags.ParseArgs([]string{"-o", "false"}, false))
+	result, err = flags.ParseArgs([]string{"-o", "false"})
+	assert.NoError(t, err)
 
-	val, err = BoolValue(opt)
+	val, err = BoolValue(result.Option(opt))
 	assert.NoError(t, err)
 	assert.False(t, val)
 
-	flags.WithOptions(opt)
-	assert.NoError(t, flags.ParseArgs([]string{"--opt", "true"}, false))
+	result, err = flags.ParseArgs([]string{"--opt", "true"})
+	assert.NoError(t, err)
 
-	val, err = BoolValue(opt)
+	val, err = BoolValue(result.Option(opt))
 	assert.NoError(t, err)
 	assert.True(t, val)
 }
@@ -114,9 +215,10 @@ func TestFlagsParseLongBoolOptionNoArgs(t *testing.T) {
 	opt := NewBool("opt", 'o', "", false)
 
 	flags.WithOptions(opt)
-	assert.NoError(t, flags.ParseArgs([]string{"--opt"}, false))
+	result, err := flags.ParseArgs([]string{"--opt"})
+	assert.NoError(t, err)
 
-	val, err := BoolValue(opt)
+	val, err := BoolValue(result.Option(opt))
 	assert.NoError(t, err)
 	assert.True(t, val)
 }
@@ -127,9 +229,10 @@ func TestFlagsParseLongOptionNoBool(t *testing.T) {
 	expectedVal := "abc"
 
 	flags.WithOptions(opt)
-	assert.NoError(t, flags.ParseArgs([]string{"--opt", expectedVal}, false))
+	result, err := flags.ParseArgs([]string{"--opt", expectedVal})
+	assert.NoError(t, err)
 
-	val, err := StringValue(opt)
+	val, err := StringValue(result.Option(opt))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedVal, val)
 }
@@ -144,45 +247,547 @@ func TestFlagsParseMultipleShortOptions(t *testing.T) {
 
 	flags := Flags{}
 	flags.WithOptions(opt1, opt2, opt3)
-	assert.NoError(t, flags.ParseArgs([]string{"-amz", opt3Value}, false))
+	result, err := flags.ParseArgs([]string{"-amz", opt3Value})
+	assert.NoError(t, err)
 
 	calledBoolOptions := []*Option{opt1, opt2}
 	for _, opt := range calledBoolOptions {
-		val, err := BoolValue(opt)
+		val, err := BoolValue(result.Option(opt))
 		assert.NoError(t, err)
 		assert.True(t, val)
 	}
 
-	val, err := BoolValue(opt4)
+	val, err := BoolValue(result.Option(opt4))
 	assert.NoError(t, err)
 	assert.False(t, val)
 
-	strVal, ok := opt3.Value.(*String)
+	strVal, ok := result.Value(opt3).(*String)
 	assert.True(t, ok)
 	assert.Equal(t, opt3Value, strVal.Value)
 }
 
+func TestFlagsParseLongOptionAttachedValue(t *testing.T) {
+	flags := Flags{}
+	opt := NewString("out", 'o', "", "")
+	flags.WithOptions(opt)
+
+	result, err := flags.ParseArgs([]string{"--out=file.txt"})
+	assert.NoError(t, err)
+
+	val, err := StringValue(result.Option(opt))
+	assert.NoError(t, err)
+	assert.Equal(t, "file.txt", val)
+
+	result, err = flags.ParseArgs([]string{"--out=a=b"})
+	assert.NoError(t, err)
+
+	val, err = StringValue(result.Option(opt))
+	assert.NoError(t, err)
+	assert.Equal(t, "a=b", val)
+}
+
+func TestFlagsParseShortOptionAttachedValue(t *testing.T) {
+	flags := Flags{}
+	boolOpt := NewBool("verbose", 'v', "", false)
+	strOpt := NewString("out", 'o', "", "")
+	flags.WithOptions(boolOpt, strOpt)
+
+	result, err := flags.ParseArgs([]string{"-vofile.txt"})
+	assert.NoError(t, err)
+
+	boolVal, err := BoolValue(result.Option(boolOpt))
+	assert.NoError(t, err)
+	assert.True(t, boolVal)
+
+	strVal, err := StringValue(result.Option(strOpt))
+	assert.NoError(t, err)
+	assert.Equal(t, "file.txt", strVal)
+}
+
+func TestFlagsParseBoolOptionAttachedValue(t *testing.T) {
+	flags := Flags{}
+	opt := NewBool("color", 'c', "", true)
+	flags.WithOptions(opt)
+
+	result, err := flags.ParseArgs([]string{"--color=false"})
+	assert.NoError(t, err)
+
+	val, err := BoolValue(result.Option(opt))
+	assert.NoError(t, err)
+	assert.False(t, val)
+
+	result, err = flags.ParseArgs([]string{"--color=TRUE"})
+	assert.NoError(t, err)
+
+	val, err = BoolValue(result.Option(opt))
+	assert.NoError(t, err)
+	assert.True(t, val)
+
+	_, err = flags.ParseArgs([]string{"--color=maybe"})
+	assert.EqualError(t, err, `invalid value "maybe" for option "--color": expected a boolean value`)
+}
+
+func TestFlagsParseNegatedBoolOption(t *testing.T) {
+	color := NewBool("color", 'c', "", true)
+	noCache := NewBool("no-cache", EmptyShort, "", false)
+	verbose := NewCounter("verbose", 'v', "", 0)
+	name := NewString("name", EmptyShort, "", "")
+	src := &Arg{Name: "SRC", Value: &String{}}
+
+	flags := Flags{}
+	flags.WithOptions(color, noCache, verbose, name)
+	flags.WithArgs(src)
+
+	result, err := flags.ParseArgs([]string{"--no-color", "--no-cache", "-vv", "--no-verbose"})
+	assert.NoError(t, err)
+
+	val, _ := BoolValue(result.Option(color))
+	assert.False(t, val)
+	assert.Equal(t, Source{Kind: SourceArgs, Arg: "--no-color"}, result.Source(color))
+
+	// the options named "no-*" take precedence over the negation
+	val, _ = BoolValue(result.Option(noCache))
+	assert.True(t, val)
+
+	count, _ := CounterValue(result.Option(verbose))
+	assert.Equal(t, 0, count)
+
+	_, err = flags.ParseArgs([]string{"--no-color=true"})
+	assert.EqualError(t, err, `invalid value "true" for option "--color": a negated option does not accept a value`)
+
+	_, err = flags.ParseArgs([]string{"--no-name"})
+	assert.EqualError(t, err, `unknown option "--no-name"`)
+
+	_, err = flags.ParseArgs([]string{"--no-colour"})
+	assert.EqualError(t, err, `unknown option "--no-colour"`)
+}
+
+func TestFlagsParseBoolLiterals(t *testing.T) {
+	color := NewBool("color", 'c', "", false)
+	src := &Arg{Name: "SRC", Value: &String{}}
+
+	flags := Flags{}
+	flags.WithOptions(color)
+	flags.WithArgs(src)
+
+	for _, literal := range []string{"true", "1", "yes", "ON", "false", "0", "No", "off"} {
+		expected, _ := parseBool(literal)
+
+		result, err := flags.ParseArgs([]string{"--color=" + literal})
+		assert.NoError(t, err, literal)
+
+		val, _ := BoolValue(result.Option(color))
+		assert.Equal(t, expected, val, literal)
+	}
+
+	// the next argument is consumed only if it is the true or false literal
+	for _, literal := range []string{"true", "TRUE", "false", "False"} {
+		for _, args := range [][]string{{"--color", literal}, {"-c", literal}} {
+			result, err := flags.ParseArgs(args)
+			assert.NoError(t, err, args)
+
+			val, _ := BoolValue(result.Option(color))
+			assert.Equal(t, strings.EqualFold(literal, "true"), val, args)
+			assert.Empty(t, result.Arg(src).Values(), args)
+		}
+	}
+
+	for _, positional := range []string{"1", "0", "yes", "off"} {
+		for _, args := range [][]string{{"--color", positional}, {"-c", positional}} {
+			result, err := flags.ParseArgs(args)
+			assert.NoError(t, err, args)
+
+			val, _ := BoolValue(result.Option(color))
+			assert.True(t, val, args)
+			assert.Equal(t, []string{positional}, result.Arg(src).Values(), args)
+		}
+	}
+
+	// the whole argument must be the literal
+	for _, args := range [][]string{{"--color", "untrue.txt"}, {"-c", "untrue.txt"}} {
+		result, err := flags.ParseArgs(args)
+		assert.NoError(t, err, args)
+
+		val, _ := BoolValue(result.Option(color))
+		assert.True(t, val, args)
+		assert.Equal(t, []string{"untrue.txt"}, result.Arg(src).Values(), args)
+	}
+}
+
+func TestFlagsParseUnknownShortOptionInCluster(t *testing.T) {
+	flags := Flags{}
+	flags.WithOptions(NewBool("", 'a', "", false))
+
+	flags.WithOptions(NewString("", 'o', "", ""))
+
+	_, err := flags.ParseArgs([]string{"-ax"})
+	assert.Error(t, err)
+
+	_, err = flags.ParseArgs([]string{"-aé"})
+
+	var unknownErr *UnknownOptionError
+	assert.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, "-é", unknownErr.Token)
+	assert.Equal(t, "é", unknownErr.Name)
+
+	result, err := flags.ParseArgs([]string{"-aoé"})
+	assert.NoError(t, err)
+	assert.Equal(t, "é", result.Option(flags.Options[1]).Value.String())
+}
+
+func TestFlagsParseLongOptionNames(t *testing.T) {
+	flags := Flags{}
+	kebabOpt := NewBool("dry-run", EmptyShort, "", false)
+	snakeOpt := NewString("log_file", EmptyShort, "", "")
+	dottedOpt := NewString("log.level", EmptyShort, "", "")
+	shortNameOpt := NewBool("dry", EmptyShort, "", false)
+	flags.WithOptions(shortNameOpt, kebabOpt, snakeOpt, dottedOpt)
+
+	result, err := flags.ParseArgs([]string{"--dry-run", "--log_file", "out.log", "--log.level=debug"})
+	assert.NoError(t, err)
+
+	boolVal, err := BoolValue(result.Option(kebabOpt))
+	assert.NoError(t, err)
+	assert.True(t, boolVal)
+
+	boolVal, err = BoolValue(result.Option(shortNameOpt))
+	assert.NoError(t, err)
+	assert.False(t, boolVal)
+
+	strVal, err := StringValue(result.Option(snakeOpt))
+	assert.NoError(t, err)
+	assert.Equal(t, "out.log", strVal)
+
+	strVal, err = StringValue(result.Option(dottedOpt))
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", strVal)
+}
+
 func TestFlagsParseSubCommand(t *testing.T) {
 	cmd := &Command{Name: "command"}
 	subCmd := &Command{Name: "subcommand"}
 	cmd.WithCommands(subCmd)
 
 	flags := Flags{Commands: []*Command{cmd}}
-	assert.NoError(t, flags.ParseArgs([]string{cmd.Name, subCmd.Name}, false))
-	assert.True(t, cmd.Called)
-	assert.True(t, subCmd.Called)
+	result, err := flags.ParseArgs([]string{cmd.Name, subCmd.Name})
+	assert.NoError(t, err)
+	assert.True(t, result.Called(cmd))
+	assert.True(t, result.Called(subCmd))
+	assert.Equal(t, []*Command{cmd, subCmd}, result.Path())
+	assert.Equal(t, subCmd, result.Command())
+}
+
+func TestFlagsParseCommandArgs(t *testing.T) {
+	src := &Arg{Name: "SRC", Value: &String{}, Required: true}
+	dst := &Arg{Name: "DST", Value: &String{}, Required: true}
+	force := NewBool("force", 'f', "", false)
+	cmd := &Command{Name: "copy"}
+	cmd.WithArgs(src, dst)
+	cmd.WithOptions(force)
+
+	flags := Flags{}
+	flags.WithCommands(cmd)
+	result, err := flags.ParseArgs([]string{"copy", "a.txt", "-f", "b.txt"})
+	assert.NoError(t, err)
+	assert.True(t, result.Called(cmd))
+	assert.Equal(t, "a.txt", result.Arg(src).Value.String())
+	assert.Equal(t, "b.txt", result.Arg(dst).Value.String())
+	assert.Equal(t, []string{"b.txt"}, result.Arg(dst).Values())
+
+	val, err := BoolValue(result.Option(force))
+	assert.NoError(t, err)
+	assert.True(t, val)
+
+	_, err = flags.ParseArgs([]string{"copy", "a.txt"})
+	assert.Error(t, err)
+
+	_, err = flags.ParseArgs([]string{"copy", "a.txt", "b.txt", "c.txt"})
+	assert.Error(t, err)
+}
+
+func TestFlagsParseVariadicArgs(t *testing.T) {
+	count := &Arg{Name: "COUNT", Value: &Int{}, Required: true}
+	files := &Arg{Name: "FILES", Value: &String{}, Variadic: true}
+
+	flags := Flags{}
+	flags.WithArgs(count, files)
+
+	result, err := flags.ParseArgs([]string{"3", "a", "b"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "3", result.Arg(count).Value.String())
+	assert.Equal(t, []string{"a", "b"}, result.Arg(files).Values())
+
+	_, err = flags.ParseArgs([]string{"three"})
+	assert.Error(t, err)
+}
+
+func TestFlagsParseArgsBeforeCommandName(t *testing.T) {
+	file := &Arg{Name: "FILE", Value: &String{}}
+	cmd := &Command{Name: "build"}
+
+	flags := Flags{}
+	flags.WithArgs(file)
+	flags.WithCommands(cmd)
+
+	result, err := flags.ParseArgs([]string{"file.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, "file.txt", result.Arg(file).Value.String())
+
+	// once a positional is given, command names are positionals too
+	result, err = flags.ParseArgs([]string{"file.txt", "build"})
+	assert.Error(t, err)
+	assert.False(t, result.Called(cmd))
+}
+
+func TestFlagsParseNegativeNumberArgs(t *testing.T) {
+	num := &Arg{Name: "NUM", Value: &Int{}, Required: true}
+	factor := &Arg{Name: "FACTOR", Value: &Float64{}}
+
+	flags := Flags{}
+	flags.WithArgs(num, factor)
+	flags.WithOptions(NewBool("verbose", 'v', "", false))
+
+	result, err := flags.ParseArgs([]string{"-5", "-v", "-1.5"})
+	assert.NoError(t, err)
+	assert.Equal(t, "-5", result.Arg(num).Value.String())
+	assert.Equal(t, "-1.500000", result.Arg(factor).Value.String())
+
+	// a digit short option makes them options again
+	flags.WithOptions(NewBool("top5", '5', "", false))
+
+	_, err = flags.ParseArgs([]string{"-5", "-v", "-1.5"})
+
+	var unknownErr *UnknownOptionError
+	assert.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, "-1", unknownErr.Token)
+
+	result, err = flags.ParseArgs([]string{"-5", "--", "-5"})
+	assert.NoError(t, err)
+	assert.Equal(t, "-5", result.Arg(num).Value.String())
+}
+
+func TestFlagsParsePassthroughArgs(t *testing.T) {
+	verbose := NewBool("verbose", 'v', "", false)
+	cmd := &Command{Name: "exec", Passthrough: true}
+	cmd.WithOptions(verbose)
+
+	flags := Flags{}
+	flags.WithCommands(cmd)
+
+	result, err := flags.ParseArgs([]string{"exec", "-v", "--", "kubectl", "get", "pods", "-o", "wide"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kubectl", "get", "pods", "-o", "wide"}, result.Rest())
+
+	val, err := BoolValue(result.Option(verbose))
+	assert.NoError(t, err)
+	assert.True(t, val)
+
+	result, err = flags.ParseArgs([]string{"exec"})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Rest())
+}
+
+func TestFlagsParseTerminatorPositionals(t *testing.T) {
+	file := &Arg{Name: "FILE", Value: &String{}, Required: true}
+	force := NewBool("force", 'f', "", false)
+
+	flags := Flags{}
+	flags.WithArgs(file)
+	flags.WithOptions(force)
+
+	result, err := flags.ParseArgs([]string{"-f", "--", "-f"})
+	assert.NoError(t, err)
+	assert.Equal(t, "-f", result.Arg(file).Value.String())
+	assert.Empty(t, result.Rest())
+
+	_, err = flags.ParseArgs([]string{"--", "a", "b"})
+	assert.Error(t, err)
+}
+
+func TestFlagsParseDoesNotModifyDefinitions(t *testing.T) {
+	opt := NewString("opt", 'o', "", "default")
+	arg := &Arg{Name: "ARG", Value: &String{}}
+	cmd := &Command{Name: "cmd"}
+
+	flags := Flags{}
+	flags.WithOptions(opt)
+	flags.WithArgs(arg)
+	flags.WithCommands(cmd)
+
+	result1, err := flags.ParseArgs([]string{"--opt", "first", "value"})
+	assert.NoError(t, err)
+
+	result2, err := flags.ParseArgs([]string{"cmd"})
+	assert.NoError(t, err)
+
+	val, err := StringValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", val)
+	assert.Equal(t, "", arg.Value.String())
+
+	val, err = StringValue(result1.Option(opt))
+	assert.NoError(t, err)
+	assert.Equal(t, "first", val)
+	assert.True(t, result1.IsSet(opt))
+	assert.Equal(t, "value", result1.Arg(arg).Value.String())
+	assert.False(t, result1.Called(cmd))
+
+	val, err = StringValue(result2.Option(opt))
+	assert.NoError(t, err)
+	assert.Equal(t, "default", val)
+	assert.False(t, result2.IsSet(opt))
+	assert.True(t, result2.Called(cmd))
+}
+
+func TestFlagsParseConcurrently(t *testing.T) {
+	defaultTime := time.Date(2020, 6, 8, 0, 0, 0, 0, time.UTC)
+	tags := NewStringSlice("tag", 't', "", []string{"default"})
+	labels := NewStringMap("label", 'l', "", map[string]string{"env": "dev"})
+	limits := NewStringToInt("limit", EmptyShort, "", map[string]int{"cpu": 1})
+	timeouts := NewDurationSlice("timeout", EmptyShort, "", []time.Duration{time.Second})
+	since := NewTime("since", EmptyShort, "", defaultTime)
+
+	flags := Flags{}
+	flags.WithOptions(tags, labels, limits, timeouts, since)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			result, err := flags.ParseArgs([]string{
+				"-t", fmt.Sprintf("a%d,b%d", i, i), "--tag", "c",
+				"-l", fmt.Sprintf("env=%d", i), "--label", "region=eu",
+				"--limit", fmt.Sprintf("cpu=%d,mem=%d", i, i*2),
+				"--timeout", fmt.Sprintf("%ds", i), "--timeout", "1d",
+				"--since", fmt.Sprintf("2020-06-%02dT00:00:00Z", i+1),
+			})
+			assert.NoError(t, err)
+
+			tagsVal, _ := StringSliceValue(result.Option(tags))
+			assert.Equal(t, []string{fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i), "c"}, tagsVal)
+
+			labelsVal, _ := StringMapValue(result.Option(labels))
+			assert.Equal(t, map[string]string{"env": fmt.Sprint(i), "region": "eu"}, labelsVal)
+
+			limitsVal, _ := StringToIntValue(result.Option(limits))
+			assert.Equal(t, map[string]int{"cpu": i, "mem": i * 2}, limitsVal)
+
+			timeoutsVal, _ := DurationSliceValue(result.Option(timeouts))
+			assert.Equal(t, []time.Duration{time.Duration(i) * time.Second, 24 * time.Hour}, timeoutsVal)
+
+			sinceVal, _ := TimeValue(result.Option(since))
+			assert.Equal(t, time.Date(2020, 6, i+1, 0, 0, 0, 0, time.UTC), sinceVal.UTC())
+		}(i)
+	}
+
+	wg.Wait()
+
+	tagsVal, _ := StringSliceValue(tags)
+	assert.Equal(t, []string{"default"}, tagsVal)
+
+	labelsVal, _ := StringMapValue(labels)
+	assert.Equal(t, map[string]string{"env": "dev"}, labelsVal)
+
+	limitsVal, _ := StringToIntValue(limits)
+	assert.Equal(t, map[string]int{"cpu": 1}, limitsVal)
+
+	timeoutsVal, _ := DurationSliceValue(timeouts)
+	assert.Equal(t, []time.Duration{time.Second}, timeoutsVal)
+
+	sinceVal, _ := TimeValue(since)
+	assert.Equal(t, defaultTime, sinceVal)
+}
+
+func TestFlagsParseSubOption(t *testing.T) {
+	rootOpt := NewBool("debug", 'd', "", false)
+	cmdOpt := NewBool("dry", 'd', "", false)
+	cmd := &Command{Name: "build"}
+	cmd.WithOptions(cmdOpt)
+
+	flags := Flags{}
+	flags.WithOptions(rootOpt)
+	flags.WithCommands(cmd)
+
+	result, err := flags.ParseArgs([]string{"build", "-d"})
+	assert.NoError(t, err)
+
+	val, err := BoolValue(result.Option(cmdOpt))
+	assert.NoError(t, err)
+	assert.True(t, val)
+
+	val, err = BoolValue(result.Option(rootOpt))
+	assert.NoError(t, err)
+	assert.False(t, val)
 }
 
-func TestFlagsParseSubOption(t *testing.T) {}
+func TestFlagsParsePersistentOptions(t *testing.T) {
+	debug := NewBool("debug", 'd', "", false)
+	debug.Persistent = true
+	dry := NewBool("dry", 'd', "", false)
+	remoteName := NewString("remote", 'r', "", "origin")
+	remoteName.Persistent = true
+	build := &Command{Name: "build"}
+	build.WithOptions(dry)
+	add := &Command{Name: "add"}
+	remote := &Command{Name: "remote"}
+	remote.WithOptions(remoteName)
+	remote.WithCommands(add)
+
+	flags := Flags{}
+	flags.WithOptions(debug)
+	flags.WithCommands(build, remote)
+
+	// the command's options shadow the inherited ones
+	result, err := flags.ParseArgs([]string{"build", "--debug", "-d"})
+	assert.NoError(t, err)
+
+	val, err := BoolValue(result.Option(debug))
+	assert.NoError(t, err)
+	assert.True(t, val)
+
+	val, err = BoolValue(result.Option(dry))
+	assert.NoError(t, err)
+	assert.True(t, val)
+
+	// the same value, whatever the level the option is given at
+	result, err = flags.ParseArgs([]string{"remote", "-r", "upstream", "add", "-d"})
+	assert.NoError(t, err)
+
+	val, err = BoolValue(result.Option(debug))
+	assert.NoError(t, err)
+	assert.True(t, val)
+
+	str, err := StringValue(result.Option(remoteName))
+	assert.NoError(t, err)
+	assert.Equal(t, "upstream", str)
+
+	result, err = flags.ParseArgs([]string{"remote", "add", "--remote=fork"})
+	assert.NoError(t, err)
+
+	str, err = StringValue(result.Option(remoteName))
+	assert.NoError(t, err)
+	assert.Equal(t, "fork", str)
+
+	// not persistent
+	_, err = flags.ParseArgs([]string{"remote", "add", "--dry"})
+	assert.Error(t, err)
+}
 
 func TestFlagsParseUnkownCommand(t *testing.T) {
 	flags := Flags{}
-	assert.Error(t, flags.ParseArgs([]string{"unknown-command"}, false))
+	_, err := flags.ParseArgs([]string{"unknown-command"})
+	assert.Error(t, err)
 }
 
 func TestFlagsParseUnkownOption(t *testing.T) {
 	flags := Flags{}
-	assert.Error(t, flags.ParseArgs([]string{"--unknown-option"}, false))
+	_, err := flags.ParseArgs([]string{"--unknown-option"})
+	assert.Error(t, err)
 }
 
 func TestFlagsParseUnknownOptionSubcommand(t *testing.T) {
@@ -190,14 +795,32 @@ func TestFlagsParseUnknownOptionSubcommand(t *testing.T) {
 	cmd := Command{Name: "cmd"}
 	flags.WithCommands(&cmd)
 
-	assert.Error(t, flags.ParseArgs([]string{"cmd", "--unknown-option"}, false))
+	_, err := flags.ParseArgs([]string{"cmd", "--unknown-option"})
+	assert.Error(t, err)
 }
 
 func TestFlagsExpectedOptionValue(t *testing.T) {
 	flags := Flags{}
 	opt := &Option{Long: "str", Value: &String{}}
 	flags.WithOptions(opt)
-	assert.Error(t, flags.ParseArgs([]string{"--str"}, false))
+	_, err := flags.ParseArgs([]string{"--str"})
+	assert.Error(t, err)
+}
+
+func TestFlagsParseEmptyOptionValue(t *testing.T) {
+	name := NewString("name", 'n', "", "default")
+
+	flags := Flags{}
+	flags.WithOptions(name)
+
+	for _, args := range [][]string{{"--name", ""}, {"-n", ""}, {"--name="}} {
+		result, err := flags.ParseArgs(args)
+		assert.NoError(t, err, args)
+		assert.True(t, result.IsSet(name), args)
+
+		val, _ := StringValue(result.Option(name))
+		assert.Equal(t, "", val, args)
+	}
 }
 
 func TestFlagsPrintMainHelp(t *testing.T) {
@@ -208,10 +831,12 @@ func TestFlagsPrintMainHelp(t *testing.T) {
 	flags.AppVersion = "1.0.0"
 
 	rootOpt := NewBool("bool", 'b', "Bool value", false)
+	nameOpt := NewString("name", 'n', "Name", "")
+	nameOpt.Required = true
 	rootCmd := &Command{Name: "cmd", Description: "This is the description"}
 
 	flags.WithCommands(rootCmd)
-	flags.WithOptions(rootOpt)
+	flags.WithOptions(rootOpt, nameOpt)
 
 	expectedOutput := `AppName version 1.0.0
 
@@ -220,6 +845,7 @@ Application description.
 Available options.
 
 --bool		-b		Bool value (default value: "false")
+--name		-n		Name (required)
 
 Available commands.
 Use --help {command} {subcommand} for details.
@@ -278,3 +904,462 @@ Use --help {command} {subcommand} for details.
 
 	assert.Equal(t, expectedOutput, testWriter.Value)
 }
+
+func TestFlagsPrintCommandArgsHelp(t *testing.T) {
+	testWriter := &testStringWriter{}
+
+	flags := Flags{}
+	flags.Init("AppName", "Application description.")
+
+	cmd := &Command{Name: "copy", Description: "Copy files"}
+	cmd.WithArgs(
+		&Arg{Name: "SRC", Description: "Source file", Value: &String{}, Required: true},
+		&Arg{Name: "DST", Description: "Destination", Value: &String{DefaultValue: "."}},
+		&Arg{Name: "EXTRA", Description: "Extra files", Value: &String{}, Variadic: true},
+	)
+	flags.WithCommands(cmd)
+
+	expectedOutput := `AppName
+
+Application description.
+
+Details for command: copy
+
+Copy files
+
+Usage: AppName copy SRC [DST] [EXTRA...]
+
+Available arguments.
+
+SRC			Source file (required)
+[DST]			Destination (default value: ".")
+[EXTRA...]		Extra files
+`
+
+	flags.PrintHelpWithArgs([]string{"./app", "copy"}, testWriter)
+
+	assert.Equal(t, expectedOutput, testWriter.Value)
+}
+
+func TestFlagsPrintPassthroughHelp(t *testing.T) {
+	testWriter := &testStringWriter{}
+
+	flags := Flags{}
+	flags.Init("AppName", "")
+	flags.WithCommands(&Command{Name: "exec", Description: "Run a command", Passthrough: true})
+
+	expectedOutput := `AppName
+
+
+Details for command: exec
+
+Run a command
+
+Usage: AppName exec [-- ARGS...]
+`
+
+	flags.PrintHelpWithArgs([]string{"./app", "exec", "--", "other"}, testWriter)
+
+	assert.Equal(t, expectedOutput, testWriter.Value)
+}
+
+func TestFlagsParseHelp(t *testing.T) {
+	cmd := &Command{Name: "copy"}
+	cmd.WithArgs(&Arg{Name: "SRC", Value: &String{}, Required: true})
+
+	flags := Flags{}
+	flags.WithOptions(HelpOption)
+	flags.WithCommands(cmd)
+
+	result, err := flags.ParseArgs([]string{"--help", "copy"})
+	assert.Equal(t, ErrHelp, err)
+	assert.True(t, result.Called(cmd))
+
+	// the shared help option is not modified
+	result, err = flags.ParseArgs([]string{"copy", "a.txt"})
+	assert.NoError(t, err)
+	assert.False(t, result.IsSet(HelpOption))
+}
+
+func TestFlagsMustParseArgs(t *testing.T) {
+	exitCode := -1
+	osExit = func(code int) { exitCode = code }
+
+	defer func() { osExit = os.Exit }()
+
+	stdout := &testStringWriter{}
+	stderr := &testStringWriter{}
+	opt := NewInt("num", 'n', "A number", 0)
+
+	flags := Flags{Stdout: stdout, Stderr: stderr}
+	flags.Init("AppName", "")
+	flags.WithOptions(opt, HelpOption)
+
+	result := flags.MustParseArgs([]string{"--num", "3"})
+	assert.Equal(t, -1, exitCode)
+
+	val, err := IntValue(result.Option(opt))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, val)
+
+	flags.MustParseArgs([]string{"--help"})
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout.Value, "AppName")
+	assert.Empty(t, stderr.Value)
+
+	stdout.Value = ""
+	flags.ExitCode = 2
+	flags.MustParseArgs([]string{"--num", "three"})
+	assert.Equal(t, 2, exitCode)
+	assert.Empty(t, stdout.Value)
+	assert.Contains(t, stderr.Value, "Error: ")
+	assert.Contains(t, stderr.Value, "AppName")
+
+	stderr.Value = ""
+	flags.ExitCode = 0
+	flags.MustParseArgs([]string{"--nun", "3"})
+	assert.Equal(t, DefaultExitCode, exitCode)
+	assert.Contains(t, stderr.Value, "Error: unknown option \"--nun\"\n\nDid you mean this?\n\t--num\n")
+}
+
+func TestFlagsExecute(t *testing.T) {
+	called := []string{}
+	opt := NewString("name", 'n', "", "")
+	add := &Command{Name: "add"}
+	add.WithOptions(opt)
+	add.Run = func(ctx context.Context, result *ParseResult) error {
+		name, _ := StringValue(result.Option(opt))
+		called = append(called, "add "+name+" "+ctx.Value(testContextKey).(string))
+
+		return nil
+	}
+
+	remote := &Command{Name: "remote"}
+	remote.WithCommands(add)
+
+	failing := &Command{Name: "fail", Run: func(ctx context.Context, result *ParseResult) error {
+		return errors.New("failure")
+	}}
+
+	stdout := &testStringWriter{}
+	flags := Flags{Stdout: stdout}
+	flags.Init("AppName", "")
+	flags.WithOptions(HelpOption)
+	flags.WithCommands(remote, failing)
+	flags.Run = func(ctx context.Context, result *ParseResult) error {
+		called = append(called, "root")
+
+		return nil
+	}
+
+	ctx := context.WithValue(context.Background(), testContextKey, "ctx")
+
+	assert.NoError(t, flags.Execute(ctx, []string{"remote", "add", "-n", "origin"}))
+	assert.NoError(t, flags.Execute(ctx, []string{}))
+	assert.Equal(t, []string{"add origin ctx", "root"}, called)
+	assert.Empty(t, stdout.Value)
+
+	assert.EqualError(t, flags.Execute(ctx, []string{"fail"}), "failure")
+	assert.Error(t, flags.Execute(ctx, []string{"unknown"}))
+
+	// no action: the help is printed
+	assert.NoError(t, flags.Execute(ctx, []string{"remote"}))
+	assert.Contains(t, stdout.Value, "Details for command: remote")
+
+	stdout.Value = ""
+	assert.NoError(t, flags.Execute(ctx, []string{"remote", "add", "--help"}))
+	assert.Contains(t, stdout.Value, "Details for command: remote add")
+	assert.Len(t, called, 2)
+}
+
+func TestFlagsExecuteHooks(t *testing.T) {
+	called := []string{}
+	hook := func(name string, err error) Action {
+		return func(ctx context.Context, result *ParseResult) error {
+			called = append(called, name)
+
+			return err
+		}
+	}
+
+	add := &Command{
+		Name:              "add",
+		Run:               hook("add", nil),
+		PreRun:            hook("add pre", nil),
+		PostRun:           hook("add post", nil),
+		PersistentPreRun:  hook("add persistent pre", nil),
+		PersistentPostRun: hook("add persistent post", nil),
+	}
+	remote := &Command{
+		Name:              "remote",
+		Run:               hook("remote", nil),
+		PreRun:            hook("remote pre", nil),
+		PostRun:           hook("remote post", nil),
+		PersistentPreRun:  hook("remote persistent pre", nil),
+		PersistentPostRun: hook("remote persistent post", nil),
+	}
+	remote.WithCommands(add)
+
+	flags := Flags{
+		Run:               hook("root", nil),
+		PreRun:            hook("root pre", nil),
+		PostRun:           hook("root post", nil),
+		PersistentPreRun:  hook("root persistent pre", nil),
+		PersistentPostRun: hook("root persistent post", nil),
+	}
+	flags.WithCommands(remote)
+
+	assert.NoError(t, flags.Execute(context.Background(), []string{"remote", "add"}))
+	assert.Equal(t, []string{
+		"root persistent pre",
+		"remote persistent pre",
+		"add persistent pre",
+		"add pre",
+		"add",
+		"add post",
+		"add persistent post",
+		"remote persistent post",
+		"root persistent post",
+	}, called)
+
+	called = []string{}
+
+	assert.NoError(t, flags.Execute(context.Background(), []string{}))
+	assert.Equal(t, []string{"root persistent pre", "root pre", "root", "root post", "root persistent post"}, called)
+
+	// errors abort the chain
+	called = []string{}
+	remote.PersistentPreRun = hook("remote persistent pre", errors.New("unauthorized"))
+
+	assert.EqualError(t, flags.Execute(context.Background(), []string{"remote", "add"}), "unauthorized")
+	assert.Equal(t, []string{"root persistent pre", "remote persistent pre"}, called)
+}
+
+func TestFlagsPrintGlobalOptionsHelp(t *testing.T) {
+	testWriter := &testStringWriter{}
+
+	debug := NewBool("debug", 'd', "Debug mode", false)
+	debug.Persistent = true
+	shadowed := NewBool("verbose", 'v', "Verbose mode", false)
+	shadowed.Persistent = true
+	cmd := &Command{Name: "cmd", Description: "Command"}
+	cmd.WithOptions(NewBool("verbose", 'v', "Verbose command", false))
+
+	flags := Flags{}
+	flags.Init("AppName", "")
+	flags.WithOptions(debug, shadowed, NewBool("local", 'l', "Root only", false))
+	flags.WithCommands(cmd)
+
+	expectedOutput := `AppName
+
+
+Details for command: cmd
+
+Command
+
+Available options.
+
+--verbose	-v		Verbose command (default value: "false")
+
+Global options.
+
+--debug		-d		Debug mode (default value: "false")
+`
+
+	flags.PrintHelpWithArgs([]string{"./app", "cmd"}, testWriter)
+
+	assert.Equal(t, expectedOutput, testWriter.Value)
+}
+
+func TestFlagsPrintConfig(t *testing.T) {
+	exitCode := -1
+	osExit = func(code int) { exitCode = code }
+
+	defer func() { osExit = os.Exit }()
+
+	stdout := &testStringWriter{}
+	num := NewInt("num", 'n', "", 1)
+	num.EnvVar = "NUM"
+	name := NewString("name", EmptyShort, "", "")
+	dryRun := NewBool("dry-run", EmptyShort, "", false)
+	src := &Arg{Name: "src", Value: &String{}, Required: true}
+	build := &Command{Name: "build", Run: func(ctx context.Context, result *ParseResult) error {
+		return errors.New("not expected")
+	}}
+	build.WithOptions(dryRun)
+	build.WithArgs(src)
+
+	flags := Flags{Stdout: stdout}
+	flags.Init("AppName", "")
+	flags.WithOptions(num, name, HelpOption, PrintConfigOption)
+	flags.WithCommands(build)
+	flags.LookupEnv = testLookupEnv(map[string]string{"NUM": "3"})
+
+	// the required arguments are not checked
+	result, err := flags.ParseArgs([]string{"build", "--dry-run", "--print-config"})
+	assert.Equal(t, ErrPrintConfig, err)
+
+	flags.PrintConfig(result, stdout)
+	expected := `Effective configuration for: AppName build
+
+--num		"3"		env NUM
+--name		""		default
+--dry-run	"true"		args[1] "--dry-run"
+`
+	assert.Equal(t, expected, stdout.Value)
+
+	stdout.Value = ""
+	flags.MustParseArgs([]string{"build", "--dry-run", "--print-config"})
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, expected, stdout.Value)
+
+	stdout.Value = ""
+	assert.NoError(t, flags.Execute(context.Background(), []string{"build", "--dry-run", "--print-config"}))
+	assert.Equal(t, expected, stdout.Value)
+}
+
+func TestFlagsPrintGroupsHelp(t *testing.T) {
+	testWriter := &testStringWriter{}
+
+	json := NewBool("json", EmptyShort, "JSON output", false)
+	table := NewBool("", 't', "Table output", false)
+	user := NewString("user", 'u', "User", "")
+	password := NewString("password", EmptyShort, "Password", "")
+
+	flags := Flags{}
+	flags.WithOptions(json, table, user, password)
+	flags.WithGroups(MutuallyExclusive(json, table), RequiredTogether(user, password))
+
+	expectedOutput := `
+Available options.
+
+--json					JSON output (default value: "false")
+			-t		Table output (default value: "false")
+--user			-u		User
+--password				Password
+
+Options' constraints.
+
+- mutually exclusive		--json, -t
+- required together		--user, --password
+`
+
+	flags.PrintHelpWithArgs([]string{"./app"}, testWriter)
+
+	assert.Equal(t, expectedOutput, testWriter.Value)
+}
+
+func TestFlagsPrintChoicesHelp(t *testing.T) {
+	testWriter := &testStringWriter{}
+
+	flags := Flags{}
+	flags.WithOptions(NewEnum("format", 'f', "Output format", []string{"json", "yaml", "table"}, "table"))
+
+	expectedOutput := `
+Available options.
+
+--format	-f		Output format (default value: "table", choices: json, yaml, table)
+`
+
+	flags.PrintHelpWithArgs([]string{"./app"}, testWriter)
+
+	assert.Equal(t, expectedOutput, testWriter.Value)
+}
+
+func TestFlagsParseCaseInsensitiveEnum(t *testing.T) {
+	format := NewEnum("format", 'f', "", []string{"json", "yaml"}, "json")
+	format.Value.(*Enum).CaseInsensitive = true
+
+	flags := Flags{}
+	flags.WithOptions(format)
+
+	result, err := flags.ParseArgs([]string{"--format", "YAML"})
+	assert.NoError(t, err)
+
+	val, _ := EnumValue(result.Option(format))
+	assert.Equal(t, "yaml", val)
+
+	_, err = flags.ParseArgs([]string{"-f", "XML"})
+
+	var valueErr *InvalidValueError
+	assert.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, format, valueErr.Option)
+
+	// the definition is not modified
+	val, _ = EnumValue(format)
+	assert.Equal(t, "json", val)
+}
+
+func TestFlagsPrintSliceHelp(t *testing.T) {
+	testWriter := &testStringWriter{}
+
+	flags := Flags{}
+	flags.WithOptions(NewStringSlice("include", 'I', "Include dirs", []string{"/usr/include", "/opt/include"}))
+
+	expectedOutput := `
+Available options.
+
+--include	-I		Include dirs (repeatable, default value: ["/usr/include", "/opt/include"])
+`
+
+	flags.PrintHelpWithArgs([]string{"./app"}, testWriter)
+
+	assert.Equal(t, expectedOutput, testWriter.Value)
+}
+
+func TestFlagsParseCounter(t *testing.T) {
+	verbose := NewCounter("verbose", 'v', "", 0)
+	debug := NewBool("debug", 'd', "", false)
+	files := &Arg{Name: "FILES", Value: &String{}, Variadic: true}
+
+	flags := Flags{}
+	flags.WithOptions(verbose, debug)
+	flags.WithArgs(files)
+
+	for args, expected := range map[string]int{
+		"":                            0,
+		"-v":                          1,
+		"-vvv":                        3,
+		"-vdv":                        2,
+		"--verbose --verbose":         2,
+		"-vv --verbose":               3,
+		"--verbose=3":                 3,
+		"--verbose=2 --verbose=false": 0,
+		"-vv --verbose=false":         0,
+		"-vv --verbose=no -v":         1,
+		"--verbose=2 -v":              3,
+	} {
+		result, err := flags.ParseArgs(strings.Fields(args))
+		assert.NoError(t, err, args)
+
+		value, _ := CounterValue(result.Option(verbose))
+		assert.Equal(t, expected, value, args)
+	}
+
+	// the next argument is never consumed as the count
+	for args, expected := range map[string]int{"-vvv 1 FILE": 3, "-vvv 2 FILE": 3, "--verbose 1 FILE": 1} {
+		result, err := flags.ParseArgs(strings.Fields(args))
+		assert.NoError(t, err, args)
+
+		value, _ := CounterValue(result.Option(verbose))
+		assert.Equal(t, expected, value, args)
+		assert.Equal(t, strings.Fields(args)[1:], result.Arg(files).Values(), args)
+	}
+
+	_, err := flags.ParseArgs([]string{"--verbose=many"})
+	assert.EqualError(t, err, `invalid value "many" for option "--verbose": expected a non-negative count`)
+
+	// the count starts from the default value
+	flags = Flags{}
+	flags.WithOptions(NewCounter("verbose", 'v', "", 2))
+
+	result, err := flags.ParseArgs([]string{"-v"})
+	assert.NoError(t, err)
+
+	value, _ := CounterValue(result.Option(flags.Options[0]))
+	assert.Equal(t, 3, value)
+
+	testWriter := &testStringWriter{}
+	flags.PrintHelpWithArgs([]string{"./app"}, testWriter)
+	assert.Contains(t, testWriter.Value, `(default value: "2")`)
+}
@@ -0,0 +1,94 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MutuallyExclusive create a group of options, at most one of which can be given
+func MutuallyExclusive(options ...*Option) *OptionGroup {
+	return &OptionGroup{Kind: GroupMutuallyExclusive, Options: options}
+}
+
+// RequiredTogether create a group of options to be given either all together or none
+func RequiredTogether(options ...*Option) *OptionGroup {
+	return &OptionGroup{Kind: GroupRequiredTogether, Options: options}
+}
+
+// OneRequired create a group of options, at least one of which must be given
+func OneRequired(options ...*Option) *OptionGroup {
+	return &OptionGroup{Kind: GroupOneRequired, Options: options}
+}
+
+// String the constraint's description, as shown in the help
+func (kind GroupKind) String() string {
+	switch kind {
+	case GroupMutuallyExclusive:
+		return "mutually exclusive"
+	case GroupRequiredTogether:
+		return "required together"
+	case GroupOneRequired:
+		return "at least one required"
+	default:
+		return "unknown"
+	}
+}
+
+// validateGroup check that the group constrains at least two options
+func validateGroup(group *OptionGroup) error {
+	if len(group.Options) < 2 {
+		return errors.New("an options' group must have at least two options")
+	}
+
+	for _, option := range group.Options {
+		if option == nil {
+			return errors.New("an options' group cannot have nil options")
+		}
+	}
+
+	return nil
+}
+
+// checkGroups check the options' groups of the root and of the called commands
+func (p *parser) checkGroups() error {
+	groups := append([]*OptionGroup{}, p.flags.Groups...)
+
+	for _, cmd := range p.result.path {
+		groups = append(groups, cmd.Groups...)
+	}
+
+	for _, group := range groups {
+		given := []*Option{}
+		missing := []*Option{}
+
+		for _, option := range group.Options {
+			if p.result.IsSet(option) {
+				given = append(given, option)
+			} else {
+				missing = append(missing, option)
+			}
+		}
+
+		switch {
+		case group.Kind == GroupMutuallyExclusive && len(given) > 1:
+			return &MutuallyExclusiveError{Options: given, Path: p.result.Path()}
+		case group.Kind == GroupRequiredTogether && len(given) > 0 && len(missing) > 0:
+			return &RequiredTogetherError{Options: given, Missing: missing, Path: p.result.Path()}
+		case group.Kind == GroupOneRequired && len(given) == 0:
+			return &OneRequiredError{Options: group.Options, Path: p.result.Path()}
+		}
+	}
+
+	return nil
+}
+
+// optionNames the options' names, quoted and comma-separated (i.e. `"--json", "-y"`)
+func optionNames(options []*Option) string {
+	names := make([]string, 0, len(options))
+	for _, option := range options {
+		names = append(names, fmt.Sprintf(`"%s"`, optionName(option)))
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,69 @@
+package flags
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupKindString(t *testing.T) {
+	assert.Equal(t, "mutually exclusive", GroupMutuallyExclusive.String())
+	assert.Equal(t, "required together", GroupRequiredTogether.String())
+	assert.Equal(t, "at least one required", GroupOneRequired.String())
+}
+
+func TestFlagsParseGroups(t *testing.T) {
+	json := NewBool("json", EmptyShort, "", false)
+	yaml := NewBool("yaml", EmptyShort, "", false)
+	table := NewBool("table", 't', "", false)
+	user := NewString("user", 'u', "", "")
+	password := NewString("password", EmptyShort, "", "")
+	password.EnvVar = "PASSWORD"
+	file := NewString("file", 'f', "", "")
+	url := NewString("url", EmptyShort, "", "")
+	get := &Command{Name: "get"}
+	get.WithOptions(file, url)
+	get.WithGroups(OneRequired(file, url))
+
+	flags := Flags{}
+	flags.WithOptions(json, yaml, table, user, password)
+	flags.WithGroups(MutuallyExclusive(json, yaml, table), RequiredTogether(user, password))
+	flags.WithCommands(get)
+	flags.LookupEnv = testLookupEnv(map[string]string{})
+
+	_, err := flags.ParseArgs([]string{"--json", "-u", "me", "get", "-f", "a"})
+
+	var togetherErr *RequiredTogetherError
+
+	assert.True(t, errors.As(err, &togetherErr))
+	assert.Equal(t, []*Option{user}, togetherErr.Options)
+	assert.Equal(t, []*Option{password}, togetherErr.Missing)
+	assert.Equal(t, `options "--user" require "--password" (command "get")`, err.Error())
+
+	// the environment variables count as given
+	flags.LookupEnv = testLookupEnv(map[string]string{"PASSWORD": "secret"})
+	_, err = flags.ParseArgs([]string{"--json", "-u", "me", "get", "-f", "a"})
+	assert.NoError(t, err)
+
+	_, err = flags.ParseArgs([]string{"--json", "-t", "get", "-f", "a"})
+
+	var exclusiveErr *MutuallyExclusiveError
+
+	assert.True(t, errors.As(err, &exclusiveErr))
+	assert.Equal(t, []*Option{json, table}, exclusiveErr.Options)
+	assert.Equal(t, `options "--json", "--table" are mutually exclusive (command "get")`, err.Error())
+
+	flags.LookupEnv = testLookupEnv(map[string]string{})
+	_, err = flags.ParseArgs([]string{"get"})
+
+	var oneErr *OneRequiredError
+
+	assert.True(t, errors.As(err, &oneErr))
+	assert.Equal(t, []*Option{file, url}, oneErr.Options)
+	assert.Equal(t, `one of the options "--file", "--url" is required (command "get")`, err.Error())
+
+	// the command's groups are evaluated only if the command is called
+	_, err = flags.ParseArgs([]string{"--yaml"})
+	assert.NoError(t, err)
+}
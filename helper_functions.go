@@ -2,27 +2,152 @@ package flags
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 )
 
+// longNamePattern valid long option name: letters, digits, "-", "_" and ".",
+// starting with a letter or a digit (i.e. dry-run, log_level, log.level)
+const longNamePattern = "[a-zA-Z0-9][a-zA-Z0-9._-]*"
+
+// shortNamePattern valid short option name: a letter or a digit
+const shortNamePattern = "[a-zA-Z0-9]"
+
 func isOption(arg string) bool {
-	return regexp.MustCompile("^--?[a-zA-Z0-9]{1,}").MatchString(arg)
+	return regexp.MustCompile("^(-" + shortNamePattern + "|--" + longNamePattern + ")").MatchString(arg)
 }
 
 func isShortOption(arg string) bool {
-	return regexp.MustCompile("^-[a-zA-Z0-9]").MatchString(arg)
+	return regexp.MustCompile("^-" + shortNamePattern).MatchString(arg)
 }
 
-func getOptionName(arg string) (string, error) {
-	matches := regexp.MustCompile("^--?([a-zA-Z0-9]{1,})").FindStringSubmatch(arg)
-	// 2 = entire string + matched string
-	matchCheckNum := 2
+// getOptionName split a long option in its name and its eventual
+// attached value (i.e. --option=value)
+func getOptionName(arg string) (string, string, bool, error) {
+	matches := regexp.MustCompile("(?s)^--(" + longNamePattern + ")(=(.*))?$").FindStringSubmatch(arg)
+	// 4 = entire string + name + attached value (with and without "=")
+	matchCheckNum := 4
 	if len(matches) != matchCheckNum {
-		return "", fmt.Errorf(`"%s" is not an option`, arg)
+		return "", "", false, fmt.Errorf(`"%s" is not an option`, arg)
+	}
+
+	return matches[1], matches[3], matches[2] != "", nil
+}
+
+// optionName the option's representation, preferring the long syntax (i.e. --debug, -d)
+func optionName(option *Option) string {
+	if option.Long != "" {
+		return "--" + option.Long
+	}
+
+	return fmt.Sprintf("-%c", option.Short)
+}
+
+// validateOption check the option's names against the accepted syntaxes
+// and the default value of the values restricted to some choices (see ChoicesValue)
+func validateOption(option *Option) error {
+	if option.Long != "" && !regexp.MustCompile("^"+longNamePattern+"$").MatchString(option.Long) {
+		return fmt.Errorf(
+			`invalid long option name "%s": only letters, digits, "-", "_" and "." are allowed`,
+			option.Long,
+		)
+	}
+
+	if option.Short != EmptyShort && !regexp.MustCompile("^"+shortNamePattern+"$").MatchString(string(option.Short)) {
+		return fmt.Errorf("invalid short option name '%c': only letters and digits are allowed", option.Short)
+	}
+
+	// the default value is checked by setting it to a copy of the value,
+	// honoring its own matching rules (i.e. Enum.CaseInsensitive)
+	if _, ok := option.Value.(ChoicesValue); ok && option.Value.DefaultValueString() != "" {
+		defaultValue := option.Value.DefaultValueString()
+		if err := cloneValue(option.Value).Set(defaultValue); err != nil {
+			return fmt.Errorf(`invalid default value "%s" of option %s: %v`, defaultValue, optionName(option), err)
+		}
+	}
+
+	return nil
+}
+
+// cloneValue copy a value, either via the Cloner interface or shallow-copying it
+func cloneValue(value Value) Value {
+	if cloner, ok := value.(Cloner); ok {
+		return cloner.Clone()
+	}
+
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Ptr || reflected.IsNil() || reflected.Elem().Kind() != reflect.Struct {
+		return value
+	}
+
+	clone := reflect.New(reflected.Elem().Type())
+	clone.Elem().Set(reflected.Elem())
+
+	return clone.Interface().(Value)
+}
+
+// levenshtein the edit distance between two strings
+func levenshtein(a string, b string) int {
+	source := []rune(a)
+	target := []rune(b)
+	previous := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
 	}
 
-	return matches[1], nil
+	for i := 1; i <= len(source); i++ {
+		current := make([]int, len(target)+1)
+		current[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+
+		previous = current
+	}
+
+	return previous[len(target)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// suggestions the candidates similar to the given name, sorted by edit distance.
+// Candidates farther than maxDistance, or entirely different (i.e. "x" and "y"), are excluded
+func suggestions(name string, candidates []string, maxDistance int) []string {
+	distances := map[string]int{}
+	result := []string{}
+
+	for _, candidate := range candidates {
+		bare := strings.TrimLeft(candidate, "-")
+		distance := levenshtein(name, bare)
+
+		if _, ok := distances[candidate]; ok || distance > maxDistance || distance >= len([]rune(bare)) {
+			continue
+		}
+
+		distances[candidate] = distance
+		result = append(result, candidate)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return distances[result[i]] < distances[result[j]]
+	})
+
+	return result
 }
 
 func textSplit(source string, maxChars int) ([]string, error) {
@@ -51,3 +176,26 @@ func textSplit(source string, maxChars int) ([]string, error) {
 
 	return result, nil
 }
+
+// choicesError the error of a value not being one of the choices
+func choicesError(choices []string) error {
+	return fmt.Errorf(`must be one of "%s"`, strings.Join(choices, `", "`))
+}
+
+// parseBool parse a boolean literal: true, 1, yes, on or false, 0, no, off (case-insensitive)
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case trueStr, "1", "yes", "on":
+		return true, nil
+	case falseStr, "0", "no", "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("expected a boolean value")
+	}
+}
+
+// isBoolLiteral check if the value is the true or false literal (case-insensitive).
+// The other literals accepted by parseBool (i.e. 1, no) are not, being likely positionals
+func isBoolLiteral(value string) bool {
+	return strings.EqualFold(value, trueStr) || strings.EqualFold(value, falseStr)
+}
@@ -0,0 +1,48 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("build", "build"))
+	assert.Equal(t, 2, levenshtein("biuld", "build"))
+	assert.Equal(t, 1, levenshtein("buil", "build"))
+	assert.Equal(t, 5, levenshtein("", "build"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+}
+
+func TestSuggestions(t *testing.T) {
+	candidates := []string{"build", "test", "--verbose", "-v", "builds"}
+
+	assert.Equal(t, []string{"build", "builds"}, suggestions("buil", candidates, 2))
+	assert.Equal(t, []string{"--verbose"}, suggestions("verbos", candidates, 2))
+	assert.Equal(t, []string{"-v"}, suggestions("v", candidates, 2))
+	assert.Empty(t, suggestions("x", candidates, 2))
+	assert.Empty(t, suggestions("deploy", candidates, 2))
+}
+
+func TestParseBool(t *testing.T) {
+	for _, literal := range []string{"true", "TRUE", "1", "yes", "Yes", "on", "ON"} {
+		val, err := parseBool(literal)
+		assert.NoError(t, err, literal)
+		assert.True(t, val, literal)
+		assert.Equal(t, strings.EqualFold(literal, "true"), isBoolLiteral(literal), literal)
+	}
+
+	for _, literal := range []string{"false", "False", "0", "no", "NO", "off", "Off"} {
+		val, err := parseBool(literal)
+		assert.NoError(t, err, literal)
+		assert.False(t, val, literal)
+		assert.Equal(t, strings.EqualFold(literal, "false"), isBoolLiteral(literal), literal)
+	}
+
+	for _, literal := range []string{"", "untrue", "truefalse", "y", "2", "--verbose=false"} {
+		_, err := parseBool(literal)
+		assert.Error(t, err, literal)
+		assert.False(t, isBoolLiteral(literal), literal)
+	}
+}
@@ -1,9 +1,16 @@
 package flags
 
+import (
+	"net"
+	"net/url"
+	"time"
+)
+
 // String string option value (and default value)
 type String struct {
 	Value        string
 	DefaultValue string
+	ValueSet     bool
 }
 
 // Bool bool option value (and default value)
@@ -54,3 +61,120 @@ type Uint64 struct {
 	DefaultValue uint64
 	ValueSet     bool
 }
+
+// Enum string option value restricted to a set of choices (and default value)
+type Enum struct {
+	Value           string
+	DefaultValue    string
+	ValueSet        bool
+	Choices         []string
+	CaseInsensitive bool // Accept the choices regardless of the case (i.e. "JSON" for "json")
+}
+
+// StringSlice string option values, accumulated on repeated occurrences (and default values)
+type StringSlice struct {
+	Value        []string
+	DefaultValue []string
+	ValueSet     bool
+	NoSplit      bool // Do not split the comma-separated values (i.e. "a,b")
+}
+
+// IntSlice integer option values, accumulated on repeated occurrences (and default values)
+type IntSlice struct {
+	Value        []int
+	DefaultValue []int
+	ValueSet     bool
+	NoSplit      bool // Do not split the comma-separated values (i.e. "1,2")
+}
+
+// Float64Slice float64 option values, accumulated on repeated occurrences (and default values)
+type Float64Slice struct {
+	Value        []float64
+	DefaultValue []float64
+	ValueSet     bool
+	NoSplit      bool // Do not split the comma-separated values (i.e. "1.5,2")
+}
+
+// DurationSlice duration option values, accumulated on repeated occurrences (and default values)
+type DurationSlice struct {
+	Value        []time.Duration
+	DefaultValue []time.Duration
+	ValueSet     bool
+	NoSplit      bool // Do not split the comma-separated values (i.e. "1s,2m")
+}
+
+// StringMap key=value option pairs, accumulated on repeated occurrences (and default pairs)
+type StringMap struct {
+	Value        map[string]string
+	DefaultValue map[string]string
+	ValueSet     bool
+	NoSplit      bool // Do not split the comma-separated pairs (i.e. "a=1,b=2")
+}
+
+// StringToInt key=integer option pairs, accumulated on repeated occurrences (and default pairs)
+type StringToInt struct {
+	Value        map[string]int
+	DefaultValue map[string]int
+	ValueSet     bool
+	NoSplit      bool // Do not split the comma-separated pairs (i.e. "a=1,b=2")
+}
+
+// Counter integer option value, incremented on every occurrence (i.e. -vvv), and default value
+type Counter struct {
+	Value        int
+	DefaultValue int
+	ValueSet     bool
+}
+
+// Duration duration option value (and default value), i.e. "1h30m", "2d", "1w"
+type Duration struct {
+	Value        time.Duration
+	DefaultValue time.Duration
+	ValueSet     bool
+}
+
+// Time time option value (and default value), either absolute or relative to now (i.e. "now-1h")
+type Time struct {
+	Value        time.Time
+	DefaultValue time.Time
+	ValueSet     bool
+	Layouts      []string // The accepted layouts, the first one used to print the value (time.RFC3339 if empty)
+}
+
+// Date date option value (and default value), either absolute or relative to today (i.e. "now-7d")
+type Date struct {
+	Value        time.Time
+	DefaultValue time.Time
+	ValueSet     bool
+	Layouts      []string // The accepted layouts, the first one used to print the value (DateLayout if empty)
+}
+
+// IP IP address option value (and default value), i.e. "10.0.0.1", "::1"
+type IP struct {
+	Value        net.IP
+	DefaultValue net.IP
+	ValueSet     bool
+}
+
+// IPNet network option value in CIDR notation (and default value), i.e. "10.0.0.0/8"
+type IPNet struct {
+	Value        *net.IPNet
+	DefaultValue *net.IPNet
+	ValueSet     bool
+}
+
+// HostPort host:port option value (and default value), i.e. "0.0.0.0:8080"
+type HostPort struct {
+	Value        string
+	DefaultValue string
+	ValueSet     bool
+	DefaultPort  int // The port added to the values without one (none if 0)
+}
+
+// URL absolute URL option value (and default value), i.e. "https://example.com/api"
+type URL struct {
+	Value          *url.URL
+	DefaultValue   *url.URL
+	ValueSet       bool
+	AllowedSchemes []string // The accepted schemes, case-insensitive (any if empty)
+}
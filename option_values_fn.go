@@ -2,8 +2,8 @@ package flags
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
+	"strings"
 )
 
 const trueStr = "true"
@@ -22,11 +22,11 @@ func NewString(long string, short rune, description string, defaultValue string)
 // StringValue return the value of a String option
 func StringValue(option *Option) (string, error) {
 	if value, ok := option.Value.(*String); ok {
-		if value.Value == "" {
-			return value.DefaultValue, nil
+		if value.ValueSet {
+			return value.Value, nil
 		}
 
-		return value.Value, nil
+		return value.DefaultValue, nil
 	}
 
 	return "", fmt.Errorf("Not a string option")
@@ -35,18 +35,18 @@ func StringValue(option *Option) (string, error) {
 // Set set the value
 func (val *String) Set(value string) error {
 	val.Value = value
+	val.ValueSet = true
 
 	return nil
 }
 
 // String representation of the value
 func (val *String) String() string {
-	v := val.Value
-	if v == "" {
-		v = val.DefaultValue
+	if val.ValueSet {
+		return val.Value
 	}
 
-	return v
+	return val.DefaultValue
 }
 
 // DefaultValueString string representation of the default value
@@ -84,8 +84,18 @@ func BoolValue(option *Option) (bool, error) {
 
 // Set set the value
 func (val *Bool) Set(value string) error {
-	rx := regexp.MustCompile("(?i)true")
-	val.Value = value == "" || rx.MatchString(value)
+	boolVal := true
+
+	if value != "" {
+		parsed, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+
+		boolVal = parsed
+	}
+
+	val.Value = boolVal
 	val.ValueSet = true
 
 	return nil
@@ -452,3 +462,148 @@ func (val *Uint64) DefaultValueString() string {
 func (val *Uint64) IsBoolValue() bool {
 	return false
 }
+
+// NewCounter create a counter option
+func NewCounter(long string, short rune, description string, defaultValue int) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &Counter{DefaultValue: defaultValue},
+	}
+}
+
+// CounterValue return the value of a Counter option
+func CounterValue(option *Option) (int, error) {
+	if value, ok := option.Value.(*Counter); ok {
+		if value.ValueSet {
+			return value.Value, nil
+		}
+
+		return value.DefaultValue, nil
+	}
+
+	return 0, fmt.Errorf("Not a counter option")
+}
+
+// Set set the value to the given non-negative count (i.e. "3"),
+// increment it ("" or "true", counting up from the default value) or reset it ("false")
+func (val *Counter) Set(value string) error {
+	if !val.ValueSet {
+		val.Value = val.DefaultValue
+	}
+
+	if count, err := strconv.ParseInt(value, 10, 0); err == nil {
+		if count < 0 {
+			return fmt.Errorf("expected a non-negative count")
+		}
+
+		val.Value = int(count)
+	} else if increment, err := parseBool(value); value == "" || (err == nil && increment) {
+		val.Value++
+	} else if err == nil {
+		val.Value = 0
+	} else {
+		return fmt.Errorf("expected a non-negative count")
+	}
+
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the value
+func (val *Counter) String() string {
+	if val.ValueSet {
+		return fmt.Sprintf("%d", val.Value)
+	}
+
+	return fmt.Sprintf("%d", val.DefaultValue)
+}
+
+// DefaultValueString string representation of the default value
+func (val *Counter) DefaultValueString() string {
+	return fmt.Sprintf("%d", val.DefaultValue)
+}
+
+// IsBoolValue check if value is boolean (the counter does not expect a value)
+func (val *Counter) IsBoolValue() bool {
+	return true
+}
+
+// IsStandalone never take the count from the next argument (i.e. -vvv 1),
+// only as attached value (i.e. --verbose=3)
+func (val *Counter) IsStandalone() bool {
+	return true
+}
+
+// NewEnum create an enum option, accepting only the given choices
+func NewEnum(long string, short rune, description string, choices []string, defaultValue string) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &Enum{DefaultValue: defaultValue, Choices: choices},
+	}
+}
+
+// EnumValue return the value of an Enum option
+func EnumValue(option *Option) (string, error) {
+	if value, ok := option.Value.(*Enum); ok {
+		if value.ValueSet {
+			return value.Value, nil
+		}
+
+		return value.DefaultValue, nil
+	}
+
+	return "", fmt.Errorf("Not an enum option")
+}
+
+// Set set the value, if it is one of the choices
+func (val *Enum) Set(value string) error {
+	for _, choice := range val.Choices {
+		if value == choice || (val.CaseInsensitive && strings.EqualFold(value, choice)) {
+			val.Value = choice
+			val.ValueSet = true
+
+			return nil
+		}
+	}
+
+	return choicesError(val.Choices)
+}
+
+// String representation of the value
+func (val *Enum) String() string {
+	if val.ValueSet {
+		return val.Value
+	}
+
+	return val.DefaultValue
+}
+
+// DefaultValueString string representation of the default value
+func (val *Enum) DefaultValueString() string {
+	return val.DefaultValue
+}
+
+// IsBoolValue check if value is boolean
+func (val *Enum) IsBoolValue() bool {
+	return false
+}
+
+// ValueChoices the valid values
+func (val *Enum) ValueChoices() []string {
+	return append([]string{}, val.Choices...)
+}
+
+// Choices the valid values of the option, nil if its value is not restricted
+// to a set of choices (see ChoicesValue). Useful for the shell completion
+func Choices(option *Option) []string {
+	if value, ok := option.Value.(ChoicesValue); ok {
+		return value.ValueChoices()
+	}
+
+	return nil
+}
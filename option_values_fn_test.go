@@ -31,6 +31,14 @@ func TestStringValueValueSet(t *testing.T) {
 	val, err := StringValue(opt)
 	assert.NoError(t, err)
 	assert.Equal(t, value, val)
+
+	// an explicit empty value is not replaced by the default one
+	assert.NoError(t, opt.Value.Set(""))
+
+	val, err = StringValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "", val)
+	assert.Equal(t, "", opt.Value.String())
 }
 
 func TestStringString(t *testing.T) {
@@ -78,6 +86,18 @@ func TestBoolValueValueSet(t *testing.T) {
 	assert.True(t, val)
 }
 
+func TestBoolSet(t *testing.T) {
+	opt := NewBool("", EmptyShort, "", true)
+	assert.NoError(t, opt.Value.Set("off"))
+	assert.Equal(t, "false", opt.Value.String())
+
+	assert.NoError(t, opt.Value.Set(""))
+	assert.Equal(t, "true", opt.Value.String())
+
+	assert.EqualError(t, opt.Value.Set("untrue"), "expected a boolean value")
+	assert.Equal(t, "true", opt.Value.String())
+}
+
 func TestBoolString(t *testing.T) {
 	value := "true"
 
@@ -368,3 +388,91 @@ func TestUint64IsBoolValue(t *testing.T) {
 	opt := NewUint64("", EmptyShort, "", 0)
 	assert.False(t, opt.Value.IsBoolValue())
 }
+
+func TestNewEnum(t *testing.T) {
+	opt := NewEnum("format", 'f', "description", []string{"json", "yaml"}, "json")
+	assert.Equal(t, "format", opt.Long)
+	assert.Equal(t, 'f', opt.Short)
+	assert.Equal(t, "description", opt.Description)
+
+	value, err := EnumValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "json", value)
+
+	_, err = EnumValue(NewString("", EmptyShort, "", ""))
+	assert.Error(t, err)
+}
+
+func TestEnumValueValueSet(t *testing.T) {
+	opt := NewEnum("", EmptyShort, "", []string{"json", "yaml"}, "json")
+	assert.NoError(t, opt.Value.Set("yaml"))
+
+	val, err := EnumValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "yaml", val)
+
+	assert.EqualError(t, opt.Value.Set("YAML"), `must be one of "json", "yaml"`)
+	assert.EqualError(t, opt.Value.Set("xml"), `must be one of "json", "yaml"`)
+
+	// the case-insensitive enums keep the choice's case
+	opt.Value.(*Enum).CaseInsensitive = true
+	assert.NoError(t, opt.Value.Set("YAML"))
+	assert.Equal(t, "yaml", opt.Value.String())
+}
+
+func TestEnumDefaultValueString(t *testing.T) {
+	opt := NewEnum("", EmptyShort, "", []string{"json", "yaml"}, "json")
+	assert.Equal(t, "json", opt.Value.DefaultValueString())
+	assert.Equal(t, "json", opt.Value.String())
+}
+
+func TestEnumIsBoolValue(t *testing.T) {
+	opt := NewEnum("", EmptyShort, "", []string{}, "")
+	assert.False(t, opt.Value.IsBoolValue())
+}
+
+func TestChoices(t *testing.T) {
+	opt := NewEnum("", EmptyShort, "", []string{"json", "yaml"}, "json")
+	assert.Equal(t, []string{"json", "yaml"}, Choices(opt))
+	assert.Nil(t, Choices(NewString("", EmptyShort, "", "")))
+}
+
+func TestNewCounter(t *testing.T) {
+	opt := NewCounter("verbose", 'v', "description", 1)
+	assert.Equal(t, "verbose", opt.Long)
+	assert.Equal(t, 'v', opt.Short)
+	assert.Equal(t, "description", opt.Description)
+	assert.True(t, opt.Value.IsBoolValue())
+	assert.Equal(t, "1", opt.Value.String())
+	assert.Equal(t, "1", opt.Value.DefaultValueString())
+	_, cumulative := opt.Value.(CumulativeValue)
+	assert.False(t, cumulative)
+
+	value, err := CounterValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	_, err = CounterValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestCounterValueValueSet(t *testing.T) {
+	opt := NewCounter("", EmptyShort, "", 1)
+
+	// the increments count up from the default value
+	assert.NoError(t, opt.Value.Set(""))
+	assert.NoError(t, opt.Value.Set("true"))
+
+	val, err := CounterValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, val)
+
+	assert.NoError(t, opt.Value.Set("5"))
+	assert.Equal(t, "5", opt.Value.String())
+
+	assert.NoError(t, opt.Value.Set("FALSE"))
+	assert.Equal(t, "0", opt.Value.String())
+
+	assert.EqualError(t, opt.Value.Set("-1"), "expected a non-negative count")
+	assert.EqualError(t, opt.Value.Set("a"), "expected a non-negative count")
+}
@@ -0,0 +1,252 @@
+package flags
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// splitPairs split the key=value pairs (see splitValues)
+func splitPairs(value string, noSplit bool) ([][2]string, error) {
+	items, err := splitValues(value, noSplit)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([][2]string, 0, len(items))
+
+	for _, item := range items {
+		separator := strings.Index(item, "=")
+		if separator == -1 {
+			return nil, fmt.Errorf(`malformed pair "%s": expected key=value`, item)
+		}
+
+		if separator == 0 {
+			return nil, fmt.Errorf(`malformed pair "%s": empty key`, item)
+		}
+
+		pairs = append(pairs, [2]string{item[:separator], item[separator+1:]})
+	}
+
+	return pairs, nil
+}
+
+// sortedPairs the key=value representations, sorted by key
+func sortedPairs(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+values[key])
+	}
+
+	return pairs
+}
+
+// NewStringMap create a key=value option
+func NewStringMap(long string, short rune, description string, defaultValue map[string]string) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &StringMap{DefaultValue: defaultValue},
+	}
+}
+
+// StringMapValue return the pairs of a StringMap option
+func StringMapValue(option *Option) (map[string]string, error) {
+	if value, ok := option.Value.(*StringMap); ok {
+		if value.ValueSet {
+			return copyStringMap(value.Value), nil
+		}
+
+		return copyStringMap(value.DefaultValue), nil
+	}
+
+	return nil, fmt.Errorf("Not a string map option")
+}
+
+// Set add the key=value pair(s)
+func (val *StringMap) Set(value string) error {
+	pairs, err := splitPairs(value, val.NoSplit)
+	if err != nil {
+		return err
+	}
+
+	if !val.ValueSet {
+		val.Value = map[string]string{}
+	}
+
+	for _, pair := range pairs {
+		val.Value[pair[0]] = pair[1]
+	}
+
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the pairs, sorted by key
+func (val *StringMap) String() string {
+	if val.ValueSet {
+		return joinValues(sortedPairs(val.Value))
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default pairs, sorted by key
+func (val *StringMap) DefaultValueString() string {
+	return joinValues(val.DefaultValueStrings())
+}
+
+// DefaultValueStrings string representations of the default pairs, sorted by key
+func (val *StringMap) DefaultValueStrings() []string {
+	return sortedPairs(val.DefaultValue)
+}
+
+// IsBoolValue check if value is boolean
+func (val *StringMap) IsBoolValue() bool {
+	return false
+}
+
+// IsCumulative check if the value accumulates the repeated occurrences
+func (val *StringMap) IsCumulative() bool {
+	return true
+}
+
+// Clone a copy of the value, not sharing the pairs
+func (val *StringMap) Clone() Value {
+	clone := *val
+	if val.Value != nil {
+		clone.Value = copyStringMap(val.Value)
+	}
+
+	return &clone
+}
+
+func copyStringMap(values map[string]string) map[string]string {
+	clone := make(map[string]string, len(values))
+	for key, value := range values {
+		clone[key] = value
+	}
+
+	return clone
+}
+
+// NewStringToInt create a key=integer option
+func NewStringToInt(long string, short rune, description string, defaultValue map[string]int) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &StringToInt{DefaultValue: defaultValue},
+	}
+}
+
+// StringToIntValue return the pairs of a StringToInt option
+func StringToIntValue(option *Option) (map[string]int, error) {
+	if value, ok := option.Value.(*StringToInt); ok {
+		if value.ValueSet {
+			return copyStringToInt(value.Value), nil
+		}
+
+		return copyStringToInt(value.DefaultValue), nil
+	}
+
+	return nil, fmt.Errorf("Not a string to int option")
+}
+
+// Set add the key=integer pair(s)
+func (val *StringToInt) Set(value string) error {
+	pairs, err := splitPairs(value, val.NoSplit)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]int, len(pairs))
+
+	for _, pair := range pairs {
+		intVal, err := strconv.ParseInt(strings.TrimSpace(pair[1]), 10, 0)
+		if err != nil {
+			return fmt.Errorf(`malformed pair "%s=%s": expected an integer value`, pair[0], pair[1])
+		}
+
+		values[pair[0]] = int(intVal)
+	}
+
+	if !val.ValueSet {
+		val.Value = map[string]int{}
+	}
+
+	for key, intVal := range values {
+		val.Value[key] = intVal
+	}
+
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the pairs, sorted by key
+func (val *StringToInt) String() string {
+	if val.ValueSet {
+		return joinValues(sortedPairs(stringToIntStrings(val.Value)))
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default pairs, sorted by key
+func (val *StringToInt) DefaultValueString() string {
+	return joinValues(val.DefaultValueStrings())
+}
+
+// DefaultValueStrings string representations of the default pairs, sorted by key
+func (val *StringToInt) DefaultValueStrings() []string {
+	return sortedPairs(stringToIntStrings(val.DefaultValue))
+}
+
+// IsBoolValue check if value is boolean
+func (val *StringToInt) IsBoolValue() bool {
+	return false
+}
+
+// IsCumulative check if the value accumulates the repeated occurrences
+func (val *StringToInt) IsCumulative() bool {
+	return true
+}
+
+// Clone a copy of the value, not sharing the pairs
+func (val *StringToInt) Clone() Value {
+	clone := *val
+	if val.Value != nil {
+		clone.Value = copyStringToInt(val.Value)
+	}
+
+	return &clone
+}
+
+func copyStringToInt(values map[string]int) map[string]int {
+	clone := make(map[string]int, len(values))
+	for key, value := range values {
+		clone[key] = value
+	}
+
+	return clone
+}
+
+func stringToIntStrings(values map[string]int) map[string]string {
+	strs := make(map[string]string, len(values))
+	for key, value := range values {
+		strs[key] = strconv.Itoa(value)
+	}
+
+	return strs
+}
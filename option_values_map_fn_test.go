@@ -0,0 +1,87 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitPairs(t *testing.T) {
+	pairs, err := splitPairs(`env=prod,"desc=a,b",expr=a=b,empty=`, false)
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]string{{"env", "prod"}, {"desc", "a,b"}, {"expr", "a=b"}, {"empty", ""}}, pairs)
+
+	_, err = splitPairs("env=prod,team", false)
+	assert.EqualError(t, err, `malformed pair "team": expected key=value`)
+
+	_, err = splitPairs("=prod", false)
+	assert.EqualError(t, err, `malformed pair "=prod": empty key`)
+}
+
+func TestStringMapValue(t *testing.T) {
+	opt := NewStringMap("label", 'l', "description", map[string]string{"team": "core", "env": "dev"})
+	assert.Equal(t, "label", opt.Long)
+	assert.Equal(t, 'l', opt.Short)
+	assert.Equal(t, "description", opt.Description)
+	assert.Equal(t, "env=dev,team=core", opt.Value.DefaultValueString())
+	assert.Equal(t, []string{"env=dev", "team=core"}, opt.Value.(CumulativeValue).DefaultValueStrings())
+
+	values, err := StringMapValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "core", "env": "dev"}, values)
+
+	// the first set replaces the default pairs
+	assert.NoError(t, opt.Value.Set("env=prod"))
+	assert.NoError(t, opt.Value.Set("a.b=1,env=test"))
+	assert.Error(t, opt.Value.Set("c=3,d"))
+
+	values, err = StringMapValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.b": "1", "env": "test"}, values)
+	assert.Equal(t, "a.b=1,env=test", opt.Value.String())
+	assert.False(t, opt.Value.IsBoolValue())
+
+	clone := opt.Value.(Cloner).Clone()
+	assert.NoError(t, clone.Set("x=y"))
+	assert.Equal(t, "a.b=1,env=test", opt.Value.String())
+
+	_, err = StringMapValue(NewString("", EmptyShort, "", ""))
+	assert.Error(t, err)
+}
+
+func TestStringToIntValue(t *testing.T) {
+	opt := NewStringToInt("", 'w', "", map[string]int{"b": 2, "a": 1})
+	assert.Equal(t, "a=1,b=2", opt.Value.String())
+
+	assert.NoError(t, opt.Value.Set("c=3"))
+	assert.NoError(t, opt.Value.Set("d=4,c=5"))
+	assert.EqualError(t, opt.Value.Set("e=6,f=x"), `malformed pair "f=x": expected an integer value`)
+
+	values, err := StringToIntValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"c": 5, "d": 4}, values)
+	assert.Equal(t, "c=5,d=4", opt.Value.String())
+
+	clone := opt.Value.(Cloner).Clone()
+	assert.NoError(t, clone.Set("x=1"))
+	assert.Equal(t, "c=5,d=4", opt.Value.String())
+
+	_, err = StringToIntValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestFlagsParseMapOptions(t *testing.T) {
+	label := NewStringMap("label", 'l', "", nil)
+
+	flags := Flags{}
+	flags.WithOptions(label)
+
+	result, err := flags.ParseArgs([]string{"--label", "env=prod", "-l", "team=core"})
+	assert.NoError(t, err)
+
+	values, _ := StringMapValue(result.Option(label))
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, values)
+
+	_, err = flags.ParseArgs([]string{"--label", "env"})
+	assert.EqualError(t, err, `invalid value "env" for option "--label": malformed pair "env": expected key=value`)
+}
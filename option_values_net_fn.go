@@ -0,0 +1,289 @@
+package flags
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NewIP create an IP address option
+func NewIP(long string, short rune, description string, defaultValue net.IP) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &IP{DefaultValue: defaultValue},
+	}
+}
+
+// IPValue return a copy of the value of an IP option (nil if neither given nor defaulted)
+func IPValue(option *Option) (net.IP, error) {
+	if value, ok := option.Value.(*IP); ok {
+		ip := value.DefaultValue
+		if value.ValueSet {
+			ip = value.Value
+		}
+
+		if ip == nil {
+			return nil, nil
+		}
+
+		return append(net.IP{}, ip...), nil
+	}
+
+	return nil, fmt.Errorf("Not an IP option")
+}
+
+// Set set the value
+func (val *IP) Set(value string) error {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return fmt.Errorf("expected an IP address")
+	}
+
+	val.Value = ip
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the value
+func (val *IP) String() string {
+	if val.ValueSet {
+		return val.Value.String()
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default value
+func (val *IP) DefaultValueString() string {
+	if val.DefaultValue == nil {
+		return ""
+	}
+
+	return val.DefaultValue.String()
+}
+
+// IsBoolValue check if value is boolean
+func (val *IP) IsBoolValue() bool {
+	return false
+}
+
+// NewIPNet create a network option (CIDR notation)
+func NewIPNet(long string, short rune, description string, defaultValue *net.IPNet) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &IPNet{DefaultValue: defaultValue},
+	}
+}
+
+// IPNetValue return a copy of the value of an IPNet option (nil if neither given nor defaulted)
+func IPNetValue(option *Option) (*net.IPNet, error) {
+	if value, ok := option.Value.(*IPNet); ok {
+		network := value.DefaultValue
+		if value.ValueSet {
+			network = value.Value
+		}
+
+		if network == nil {
+			return nil, nil
+		}
+
+		return &net.IPNet{IP: append(net.IP{}, network.IP...), Mask: append(net.IPMask{}, network.Mask...)}, nil
+	}
+
+	return nil, fmt.Errorf("Not an IP network option")
+}
+
+// Set set the value
+func (val *IPNet) Set(value string) error {
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf(`expected a CIDR notation (i.e. "10.0.0.0/8")`)
+	}
+
+	val.Value = network
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the value
+func (val *IPNet) String() string {
+	if val.ValueSet {
+		return val.Value.String()
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default value
+func (val *IPNet) DefaultValueString() string {
+	if val.DefaultValue == nil {
+		return ""
+	}
+
+	return val.DefaultValue.String()
+}
+
+// IsBoolValue check if value is boolean
+func (val *IPNet) IsBoolValue() bool {
+	return false
+}
+
+// hostNamePattern a host name (i.e. "localhost", "api.example.com", "10.0.0.1")
+var hostNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]([a-zA-Z0-9_.-]*[a-zA-Z0-9_.])?$`)
+
+// NewHostPort create a host:port option, adding the default port (if not 0)
+// to the values without one (i.e. "localhost" for "localhost:8080")
+func NewHostPort(long string, short rune, description string, defaultValue string, defaultPort int) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &HostPort{DefaultValue: defaultValue, DefaultPort: defaultPort},
+	}
+}
+
+// HostPortValue return the value of a HostPort option
+func HostPortValue(option *Option) (string, error) {
+	if value, ok := option.Value.(*HostPort); ok {
+		if value.ValueSet {
+			return value.Value, nil
+		}
+
+		return value.DefaultValue, nil
+	}
+
+	return "", fmt.Errorf("Not a host:port option")
+}
+
+// Set set the value, adding the default port if missing
+func (val *HostPort) Set(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil && val.DefaultPort != 0 {
+		host, port, err = net.SplitHostPort(net.JoinHostPort(strings.Trim(value, "[]"), strconv.Itoa(val.DefaultPort)))
+	}
+
+	// the host is either empty (i.e. ":8080"), a host name or an IP address (i.e. "[::1]:80", not "a:b:c")
+	if err != nil || (host != "" && !hostNamePattern.MatchString(host) && net.ParseIP(host) == nil) {
+		return fmt.Errorf(`expected host:port (i.e. "localhost:8080")`)
+	}
+
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf(`invalid port "%s"`, port)
+	}
+
+	val.Value = net.JoinHostPort(host, port)
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the value
+func (val *HostPort) String() string {
+	if val.ValueSet {
+		return val.Value
+	}
+
+	return val.DefaultValue
+}
+
+// DefaultValueString string representation of the default value
+func (val *HostPort) DefaultValueString() string {
+	return val.DefaultValue
+}
+
+// IsBoolValue check if value is boolean
+func (val *HostPort) IsBoolValue() bool {
+	return false
+}
+
+// NewURL create an absolute URL option, restricted to the allowed schemes (any if none)
+func NewURL(long string, short rune, description string, defaultValue *url.URL, allowedSchemes ...string) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &URL{DefaultValue: defaultValue, AllowedSchemes: allowedSchemes},
+	}
+}
+
+// URLValue return a copy of the value of an URL option (nil if neither given nor defaulted)
+func URLValue(option *Option) (*url.URL, error) {
+	if value, ok := option.Value.(*URL); ok {
+		parsed := value.DefaultValue
+		if value.ValueSet {
+			parsed = value.Value
+		}
+
+		if parsed == nil {
+			return nil, nil
+		}
+
+		copied := *parsed
+
+		return &copied, nil
+	}
+
+	return nil, fmt.Errorf("Not an URL option")
+}
+
+// Set set the value, if it is an absolute URL with an allowed scheme.
+// The opaque URLs (i.e. "https:example.com") and, but for the file scheme,
+// the URLs without host (i.e. "https://") are rejected
+func (val *URL) Set(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || !parsed.IsAbs() || parsed.Opaque != "" ||
+		(parsed.Host == "" && !strings.EqualFold(parsed.Scheme, "file")) {
+		return fmt.Errorf(`expected an absolute URL (i.e. "https://example.com")`)
+	}
+
+	if len(val.AllowedSchemes) > 0 {
+		allowed := false
+
+		for _, scheme := range val.AllowedSchemes {
+			allowed = allowed || strings.EqualFold(parsed.Scheme, scheme)
+		}
+
+		if !allowed {
+			return fmt.Errorf(
+				`scheme "%s" not allowed, expected one of "%s"`, parsed.Scheme, strings.Join(val.AllowedSchemes, `", "`),
+			)
+		}
+	}
+
+	val.Value = parsed
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the value
+func (val *URL) String() string {
+	if val.ValueSet {
+		return val.Value.String()
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default value
+func (val *URL) DefaultValueString() string {
+	if val.DefaultValue == nil {
+		return ""
+	}
+
+	return val.DefaultValue.String()
+}
+
+// IsBoolValue check if value is boolean
+func (val *URL) IsBoolValue() bool {
+	return false
+}
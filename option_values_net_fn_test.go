@@ -0,0 +1,165 @@
+package flags
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPValue(t *testing.T) {
+	opt := NewIP("bind", 'b', "description", net.IPv4(127, 0, 0, 1))
+
+	// the getter returns a copy, not the default
+	value, err := IPValue(opt)
+	assert.NoError(t, err)
+	value[15] = 2
+	assert.Equal(t, "127.0.0.1", opt.Value.String())
+
+	assert.Equal(t, "bind", opt.Long)
+	assert.Equal(t, 'b', opt.Short)
+	assert.Equal(t, "description", opt.Description)
+	assert.Equal(t, "127.0.0.1", opt.Value.DefaultValueString())
+	assert.False(t, opt.Value.IsBoolValue())
+
+	assert.NoError(t, opt.Value.Set("::1"))
+	assert.EqualError(t, opt.Value.Set("10.0.0"), "expected an IP address")
+
+	value, err = IPValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, net.IPv6loopback, value)
+	assert.Equal(t, "::1", opt.Value.String())
+
+	assert.Equal(t, "", (&IP{}).DefaultValueString())
+
+	value, err = IPValue(&Option{Value: &IP{}})
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	_, err = IPValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestIPNetValue(t *testing.T) {
+	_, defaultNet, _ := net.ParseCIDR("192.168.0.0/16")
+
+	opt := NewIPNet("allow", 'a', "", defaultNet)
+
+	// the getter returns a copy, not the default
+	value, err := IPNetValue(opt)
+	assert.NoError(t, err)
+	value.IP[0] = 10
+	value.Mask[1] = 0
+	assert.Equal(t, "192.168.0.0/16", opt.Value.String())
+	assert.False(t, opt.Value.IsBoolValue())
+
+	assert.NoError(t, opt.Value.Set("10.1.2.3/8"))
+	assert.EqualError(t, opt.Value.Set("10.0.0.0"), `expected a CIDR notation (i.e. "10.0.0.0/8")`)
+
+	value, err = IPNetValue(opt)
+	assert.NoError(t, err)
+	assert.True(t, value.Contains(net.IPv4(10, 20, 30, 40)))
+	assert.Equal(t, "10.0.0.0/8", opt.Value.String())
+
+	assert.Equal(t, "", (&IPNet{}).DefaultValueString())
+
+	value, err = IPNetValue(&Option{Value: &IPNet{}})
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	_, err = IPNetValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestHostPortValue(t *testing.T) {
+	opt := NewHostPort("listen", 'l', "", "0.0.0.0:8080", 80)
+	assert.Equal(t, "0.0.0.0:8080", opt.Value.DefaultValueString())
+	assert.False(t, opt.Value.IsBoolValue())
+
+	for value, expected := range map[string]string{
+		"localhost:9090": "localhost:9090",
+		":9090":          ":9090",
+		"localhost":      "localhost:80",
+		"::1":            "[::1]:80",
+		"[::1]":          "[::1]:80",
+		"[::1]:9090":     "[::1]:9090",
+	} {
+		assert.NoError(t, opt.Value.Set(value), value)
+
+		hostPort, err := HostPortValue(opt)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, hostPort, value)
+	}
+
+	assert.EqualError(t, opt.Value.Set("localhost:http"), `invalid port "http"`)
+	assert.EqualError(t, opt.Value.Set("localhost:65536"), `invalid port "65536"`)
+	assert.EqualError(t, opt.Value.Set("a:b:c"), `expected host:port (i.e. "localhost:8080")`)
+	assert.EqualError(t, opt.Value.Set("[a:b]:80"), `expected host:port (i.e. "localhost:8080")`)
+	assert.EqualError(t, opt.Value.Set("a b"), `expected host:port (i.e. "localhost:8080")`)
+	assert.EqualError(t, opt.Value.Set("-host:80"), `expected host:port (i.e. "localhost:8080")`)
+
+	opt.Value = &HostPort{}
+	assert.EqualError(t, opt.Value.Set("localhost"), `expected host:port (i.e. "localhost:8080")`)
+
+	_, err := HostPortValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestURLValue(t *testing.T) {
+	defaultURL, _ := url.Parse("https://example.com")
+
+	opt := NewURL("endpoint", 'e', "", defaultURL, "http", "https")
+	assert.Equal(t, "https://example.com", opt.Value.DefaultValueString())
+	assert.False(t, opt.Value.IsBoolValue())
+
+	assert.NoError(t, opt.Value.Set("HTTPS://api.example.com/v1?q=1"))
+	assert.EqualError(t, opt.Value.Set("ftp://example.com"), `scheme "ftp" not allowed, expected one of "http", "https"`)
+	assert.EqualError(t, opt.Value.Set("/relative/path"), `expected an absolute URL (i.e. "https://example.com")`)
+	assert.EqualError(t, opt.Value.Set("http://[::1"), `expected an absolute URL (i.e. "https://example.com")`)
+	assert.EqualError(t, opt.Value.Set("https:example.com"), `expected an absolute URL (i.e. "https://example.com")`)
+	assert.EqualError(t, opt.Value.Set("https://"), `expected an absolute URL (i.e. "https://example.com")`)
+	assert.EqualError(t, opt.Value.Set("https:///path"), `expected an absolute URL (i.e. "https://example.com")`)
+
+	value, err := URLValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "api.example.com", value.Host)
+	assert.Equal(t, "https://api.example.com/v1?q=1", opt.Value.String())
+
+	// the getter returns a copy
+	value.Host = "changed"
+	assert.Equal(t, "https://api.example.com/v1?q=1", opt.Value.String())
+
+	opt.Value = &URL{}
+	assert.Equal(t, "", opt.Value.String())
+	assert.NoError(t, opt.Value.Set("ftp://example.com"))
+	assert.NoError(t, opt.Value.Set("file:///etc/hosts"))
+
+	value, err = URLValue(&Option{Value: &URL{}})
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	_, err = URLValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestFlagsParseNetworkOptions(t *testing.T) {
+	listen := NewHostPort("listen", 'l', "", "", 8080)
+	endpoint := NewURL("endpoint", EmptyShort, "", nil, "https")
+
+	flags := Flags{}
+	flags.WithOptions(listen, endpoint)
+
+	result, err := flags.ParseArgs([]string{"--listen", "0.0.0.0", "--endpoint=https://example.com"})
+	assert.NoError(t, err)
+
+	hostPort, _ := HostPortValue(result.Option(listen))
+	assert.Equal(t, "0.0.0.0:8080", hostPort)
+
+	_, err = flags.ParseArgs([]string{"--endpoint", "http://example.com"})
+	assert.EqualError(
+		t,
+		err,
+		`invalid value "http://example.com" for option "--endpoint": scheme "http" not allowed, expected one of "https"`,
+	)
+}
@@ -0,0 +1,378 @@
+package flags
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// splitValues split the comma-separated values, honoring the CSV quoting
+// (i.e. `a,"b,c"` for "a" and "b,c")
+func splitValues(value string, noSplit bool) ([]string, error) {
+	if noSplit || value == "" {
+		return []string{value}, nil
+	}
+
+	reader := csv.NewReader(strings.NewReader(value))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) != 1 {
+		return nil, fmt.Errorf("expected a single line of comma-separated values")
+	}
+
+	return records[0], nil
+}
+
+// joinValues join the values, quoting them if needed (see splitValues)
+func joinValues(values []string) string {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	_ = writer.Write(values)
+	writer.Flush()
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// NewStringSlice create a string slice option
+func NewStringSlice(long string, short rune, description string, defaultValue []string) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &StringSlice{DefaultValue: defaultValue},
+	}
+}
+
+// StringSliceValue return the values of a StringSlice option
+func StringSliceValue(option *Option) ([]string, error) {
+	if value, ok := option.Value.(*StringSlice); ok {
+		if value.ValueSet {
+			return append([]string{}, value.Value...), nil
+		}
+
+		return append([]string{}, value.DefaultValue...), nil
+	}
+
+	return nil, fmt.Errorf("Not a string slice option")
+}
+
+// Set add the value(s)
+func (val *StringSlice) Set(value string) error {
+	items, err := splitValues(value, val.NoSplit)
+	if err != nil {
+		return err
+	}
+
+	if !val.ValueSet {
+		val.Value = []string{}
+	}
+
+	val.Value = append(val.Value, items...)
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the values
+func (val *StringSlice) String() string {
+	if val.ValueSet {
+		return joinValues(val.Value)
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default values
+func (val *StringSlice) DefaultValueString() string {
+	return joinValues(val.DefaultValue)
+}
+
+// DefaultValueStrings string representations of the default values
+func (val *StringSlice) DefaultValueStrings() []string {
+	return append([]string{}, val.DefaultValue...)
+}
+
+// IsBoolValue check if value is boolean
+func (val *StringSlice) IsBoolValue() bool {
+	return false
+}
+
+// IsCumulative check if the value accumulates the repeated occurrences
+func (val *StringSlice) IsCumulative() bool {
+	return true
+}
+
+// NewIntSlice create an int slice option
+func NewIntSlice(long string, short rune, description string, defaultValue []int) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &IntSlice{DefaultValue: defaultValue},
+	}
+}
+
+// IntSliceValue return the values of an IntSlice option
+func IntSliceValue(option *Option) ([]int, error) {
+	if value, ok := option.Value.(*IntSlice); ok {
+		if value.ValueSet {
+			return append([]int{}, value.Value...), nil
+		}
+
+		return append([]int{}, value.DefaultValue...), nil
+	}
+
+	return nil, fmt.Errorf("Not an int slice option")
+}
+
+// Set add the value(s)
+func (val *IntSlice) Set(value string) error {
+	items, err := splitValues(value, val.NoSplit)
+	if err != nil {
+		return err
+	}
+
+	values := make([]int, 0, len(items))
+
+	for _, item := range items {
+		intVal, err := strconv.ParseInt(strings.TrimSpace(item), 10, 0)
+		if err != nil {
+			return err
+		}
+
+		values = append(values, int(intVal))
+	}
+
+	if !val.ValueSet {
+		val.Value = []int{}
+	}
+
+	val.Value = append(val.Value, values...)
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the values
+func (val *IntSlice) String() string {
+	if val.ValueSet {
+		return joinValues(intStrings(val.Value))
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default values
+func (val *IntSlice) DefaultValueString() string {
+	return joinValues(val.DefaultValueStrings())
+}
+
+// DefaultValueStrings string representations of the default values
+func (val *IntSlice) DefaultValueStrings() []string {
+	return intStrings(val.DefaultValue)
+}
+
+// IsBoolValue check if value is boolean
+func (val *IntSlice) IsBoolValue() bool {
+	return false
+}
+
+// IsCumulative check if the value accumulates the repeated occurrences
+func (val *IntSlice) IsCumulative() bool {
+	return true
+}
+
+func intStrings(values []int) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, strconv.Itoa(value))
+	}
+
+	return strs
+}
+
+// NewFloat64Slice create a float64 slice option
+func NewFloat64Slice(long string, short rune, description string, defaultValue []float64) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &Float64Slice{DefaultValue: defaultValue},
+	}
+}
+
+// Float64SliceValue return the values of a Float64Slice option
+func Float64SliceValue(option *Option) ([]float64, error) {
+	if value, ok := option.Value.(*Float64Slice); ok {
+		if value.ValueSet {
+			return append([]float64{}, value.Value...), nil
+		}
+
+		return append([]float64{}, value.DefaultValue...), nil
+	}
+
+	return nil, fmt.Errorf("Not a float64 slice option")
+}
+
+// Set add the value(s)
+func (val *Float64Slice) Set(value string) error {
+	items, err := splitValues(value, val.NoSplit)
+	if err != nil {
+		return err
+	}
+
+	values := make([]float64, 0, len(items))
+
+	for _, item := range items {
+		floatVal, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil {
+			return err
+		}
+
+		values = append(values, floatVal)
+	}
+
+	if !val.ValueSet {
+		val.Value = []float64{}
+	}
+
+	val.Value = append(val.Value, values...)
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the values
+func (val *Float64Slice) String() string {
+	if val.ValueSet {
+		return joinValues(float64Strings(val.Value))
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default values
+func (val *Float64Slice) DefaultValueString() string {
+	return joinValues(val.DefaultValueStrings())
+}
+
+// DefaultValueStrings string representations of the default values
+func (val *Float64Slice) DefaultValueStrings() []string {
+	return float64Strings(val.DefaultValue)
+}
+
+// IsBoolValue check if value is boolean
+func (val *Float64Slice) IsBoolValue() bool {
+	return false
+}
+
+// IsCumulative check if the value accumulates the repeated occurrences
+func (val *Float64Slice) IsCumulative() bool {
+	return true
+}
+
+func float64Strings(values []float64) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, strconv.FormatFloat(value, 'g', -1, 64))
+	}
+
+	return strs
+}
+
+// NewDurationSlice create a duration slice option
+func NewDurationSlice(long string, short rune, description string, defaultValue []time.Duration) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &DurationSlice{DefaultValue: defaultValue},
+	}
+}
+
+// DurationSliceValue return the values of a DurationSlice option
+func DurationSliceValue(option *Option) ([]time.Duration, error) {
+	if value, ok := option.Value.(*DurationSlice); ok {
+		if value.ValueSet {
+			return append([]time.Duration{}, value.Value...), nil
+		}
+
+		return append([]time.Duration{}, value.DefaultValue...), nil
+	}
+
+	return nil, fmt.Errorf("Not a duration slice option")
+}
+
+// Set add the value(s)
+func (val *DurationSlice) Set(value string) error {
+	items, err := splitValues(value, val.NoSplit)
+	if err != nil {
+		return err
+	}
+
+	values := make([]time.Duration, 0, len(items))
+
+	for _, item := range items {
+		duration, err := parseDuration(strings.TrimSpace(item))
+		if err != nil {
+			return err
+		}
+
+		values = append(values, duration)
+	}
+
+	if !val.ValueSet {
+		val.Value = []time.Duration{}
+	}
+
+	val.Value = append(val.Value, values...)
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the values
+func (val *DurationSlice) String() string {
+	if val.ValueSet {
+		return joinValues(durationStrings(val.Value))
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default values
+func (val *DurationSlice) DefaultValueString() string {
+	return joinValues(val.DefaultValueStrings())
+}
+
+// DefaultValueStrings string representations of the default values
+func (val *DurationSlice) DefaultValueStrings() []string {
+	return durationStrings(val.DefaultValue)
+}
+
+// IsBoolValue check if value is boolean
+func (val *DurationSlice) IsBoolValue() bool {
+	return false
+}
+
+// IsCumulative check if the value accumulates the repeated occurrences
+func (val *DurationSlice) IsCumulative() bool {
+	return true
+}
+
+func durationStrings(values []time.Duration) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, formatDuration(value))
+	}
+
+	return strs
+}
@@ -0,0 +1,133 @@
+package flags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitValues(t *testing.T) {
+	values, err := splitValues(`a,"b,c",d`, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b,c", "d"}, values)
+
+	values, err = splitValues("a,b", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a,b"}, values)
+
+	values, err = splitValues("", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, values)
+
+	_, err = splitValues(`a,"b`, false)
+	assert.Error(t, err)
+
+	_, err = splitValues("a\nb", false)
+	assert.Error(t, err)
+
+	assert.Equal(t, `a,"b,c",d`, joinValues([]string{"a", "b,c", "d"}))
+	assert.Equal(t, "", joinValues([]string{}))
+}
+
+func TestStringSliceValue(t *testing.T) {
+	opt := NewStringSlice("include", 'I', "description", []string{"default"})
+	assert.Equal(t, "include", opt.Long)
+	assert.Equal(t, 'I', opt.Short)
+	assert.Equal(t, "description", opt.Description)
+
+	values, err := StringSliceValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default"}, values)
+
+	// the first set replaces the default values
+	assert.NoError(t, opt.Value.Set("a,b"))
+	assert.NoError(t, opt.Value.Set("c"))
+
+	values, err = StringSliceValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+	assert.Equal(t, "a,b,c", opt.Value.String())
+	assert.Equal(t, "default", opt.Value.DefaultValueString())
+	assert.False(t, opt.Value.IsBoolValue())
+	assert.True(t, opt.Value.(CumulativeValue).IsCumulative())
+
+	opt.Value = &StringSlice{NoSplit: true}
+	assert.NoError(t, opt.Value.Set("a,b"))
+
+	values, _ = StringSliceValue(opt)
+	assert.Equal(t, []string{"a,b"}, values)
+
+	_, err = StringSliceValue(NewString("", EmptyShort, "", ""))
+	assert.Error(t, err)
+}
+
+func TestIntSliceValue(t *testing.T) {
+	opt := NewIntSlice("", 'n', "", []int{1, 2})
+	assert.Equal(t, "1,2", opt.Value.String())
+	assert.Equal(t, []string{"1", "2"}, opt.Value.(CumulativeValue).DefaultValueStrings())
+
+	assert.NoError(t, opt.Value.Set("3, 4"))
+	assert.NoError(t, opt.Value.Set("5"))
+	assert.Error(t, opt.Value.Set("6,a"))
+
+	values, err := IntSliceValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, values)
+	assert.Equal(t, "3,4,5", opt.Value.String())
+
+	_, err = IntSliceValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestFloat64SliceValue(t *testing.T) {
+	opt := NewFloat64Slice("", 'f', "", []float64{0.5})
+	assert.Equal(t, "0.5", opt.Value.DefaultValueString())
+
+	assert.NoError(t, opt.Value.Set("1.5,2"))
+	assert.Error(t, opt.Value.Set("a"))
+
+	values, err := Float64SliceValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1.5, 2}, values)
+	assert.Equal(t, "1.5,2", opt.Value.String())
+
+	_, err = Float64SliceValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestDurationSliceValue(t *testing.T) {
+	opt := NewDurationSlice("", 'd', "", []time.Duration{time.Second})
+	assert.Equal(t, "1s", opt.Value.DefaultValueString())
+
+	assert.NoError(t, opt.Value.Set("1m,2h,1d"))
+	assert.Error(t, opt.Value.Set("2"))
+
+	values, err := DurationSliceValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Minute, 2 * time.Hour, 24 * time.Hour}, values)
+	assert.Equal(t, "1m,2h,1d", opt.Value.String())
+
+	_, err = DurationSliceValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestFlagsParseSliceOptions(t *testing.T) {
+	include := NewStringSlice("include", 'I', "", []string{"/usr/include"})
+
+	flags := Flags{}
+	flags.WithOptions(include)
+
+	result, err := flags.ParseArgs([]string{"-I", "dir1", "--include=dir2,dir3", "-Idir4"})
+	assert.NoError(t, err)
+
+	values, _ := StringSliceValue(result.Option(include))
+	assert.Equal(t, []string{"dir1", "dir2", "dir3", "dir4"}, values)
+
+	// the definition is not modified
+	result, err = flags.ParseArgs([]string{})
+	assert.NoError(t, err)
+
+	values, _ = StringSliceValue(result.Option(include))
+	assert.Equal(t, []string{"/usr/include"}, values)
+}
@@ -0,0 +1,342 @@
+package flags
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout the default layout of the Date values
+const DateLayout = "2006-01-02"
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// timeNow the current time, used by the relative times (replaced in tests)
+var timeNow = time.Now
+
+// longUnitsPattern the day (d) and week (w) components of a duration (i.e. "2d" in "2d12h")
+var longUnitsPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)([dw])`)
+
+// relativeTimePattern a time relative to now (i.e. "now", "now-1h", "now+2d")
+var relativeTimePattern = regexp.MustCompile(`^now(?:([-+])(.+))?$`)
+
+// parseDuration parse a duration (see time.ParseDuration), accepting the
+// day (d) and week (w) units, too (i.e. "1w", "2d12h", "-1.5d"),
+// returning an error when the duration overflows
+func parseDuration(value string) (time.Duration, error) {
+	matches := longUnitsPattern.FindAllStringSubmatch(value, -1)
+	if len(matches) == 0 {
+		return time.ParseDuration(value)
+	}
+
+	unsigned := value
+	negative := false
+
+	if unsigned[0] == '-' || unsigned[0] == '+' {
+		negative = unsigned[0] == '-'
+		unsigned = unsigned[1:]
+	}
+
+	var total float64
+
+	for _, match := range matches {
+		amount, _ := strconv.ParseFloat(match[1], 64) // always valid, given the pattern
+		unit := day
+
+		if match[2] == "w" {
+			unit = week
+		}
+
+		total += amount * float64(unit)
+	}
+
+	// float64(math.MaxInt64) is 2^63, which already overflows
+	if total >= float64(math.MaxInt64) {
+		return 0, fmt.Errorf(`invalid duration "%s"`, value)
+	}
+
+	duration := time.Duration(total)
+
+	if rest := longUnitsPattern.ReplaceAllString(unsigned, ""); rest != "" {
+		parsed, err := time.ParseDuration(rest)
+		if err != nil || parsed < 0 || strings.HasPrefix(rest, "+") || parsed > math.MaxInt64-duration {
+			return 0, fmt.Errorf(`invalid duration "%s"`, value)
+		}
+
+		duration += parsed
+	}
+
+	if negative {
+		duration = -duration
+	}
+
+	return duration, nil
+}
+
+// formatDuration the duration as accepted by parseDuration, using the
+// weeks and days units and omitting the zero components (i.e. "1w2d3h", "1h30m")
+func formatDuration(duration time.Duration) string {
+	sign := ""
+	weeks := duration / week
+	duration %= week
+
+	// negated after the division, -math.MinInt64 overflowing
+	if duration < 0 || weeks < 0 {
+		sign = "-"
+		weeks = -weeks
+		duration = -duration
+	}
+
+	formatted := ""
+
+	if weeks > 0 {
+		formatted += fmt.Sprintf("%dw", weeks)
+	}
+
+	if days := duration / day; days > 0 {
+		formatted += fmt.Sprintf("%dd", days)
+		duration -= days * day
+	}
+
+	if duration > 0 || formatted == "" {
+		rest := duration.String()
+		if strings.HasSuffix(rest, "m0s") {
+			rest = strings.TrimSuffix(rest, "0s")
+		}
+
+		if strings.HasSuffix(rest, "h0m") {
+			rest = strings.TrimSuffix(rest, "0m")
+		}
+
+		formatted += rest
+	}
+
+	return sign + formatted
+}
+
+// parseTime parse a time relative to now (i.e. "now-1h") or in one of the layouts
+func parseTime(value string, layouts []string) (time.Time, error) {
+	if match := relativeTimePattern.FindStringSubmatch(value); match != nil {
+		now := timeNow()
+		if match[1] == "" {
+			return now, nil
+		}
+
+		offset, err := parseDuration(match[2])
+		if err != nil || strings.HasPrefix(match[2], "-") || strings.HasPrefix(match[2], "+") {
+			return time.Time{}, fmt.Errorf(`invalid relative time "%s"`, value)
+		}
+
+		if match[1] == "-" {
+			offset = -offset
+		}
+
+		return now.Add(offset), nil
+	}
+
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf(`expected a time like "%s" or relative to now (i.e. "now-1h")`, layouts[0])
+}
+
+// formatTime the time in the layout, empty for the zero time
+func formatTime(value time.Time, layout string) string {
+	if value.IsZero() {
+		return ""
+	}
+
+	return value.Format(layout)
+}
+
+// NewDuration create a duration option
+func NewDuration(long string, short rune, description string, defaultValue time.Duration) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &Duration{DefaultValue: defaultValue},
+	}
+}
+
+// DurationValue return the value of a Duration option
+func DurationValue(option *Option) (time.Duration, error) {
+	if value, ok := option.Value.(*Duration); ok {
+		if value.ValueSet {
+			return value.Value, nil
+		}
+
+		return value.DefaultValue, nil
+	}
+
+	return 0, fmt.Errorf("Not a duration option")
+}
+
+// Set set the value
+func (val *Duration) Set(value string) error {
+	duration, err := parseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	val.Value = duration
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the value
+func (val *Duration) String() string {
+	if val.ValueSet {
+		return formatDuration(val.Value)
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default value
+func (val *Duration) DefaultValueString() string {
+	return formatDuration(val.DefaultValue)
+}
+
+// IsBoolValue check if value is boolean
+func (val *Duration) IsBoolValue() bool {
+	return false
+}
+
+// NewTime create a time option, accepting the RFC3339 layout
+func NewTime(long string, short rune, description string, defaultValue time.Time) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &Time{DefaultValue: defaultValue},
+	}
+}
+
+// TimeValue return the value of a Time option
+func TimeValue(option *Option) (time.Time, error) {
+	if value, ok := option.Value.(*Time); ok {
+		if value.ValueSet {
+			return value.Value, nil
+		}
+
+		return value.DefaultValue, nil
+	}
+
+	return time.Time{}, fmt.Errorf("Not a time option")
+}
+
+// layouts the accepted layouts
+func (val *Time) layouts() []string {
+	if len(val.Layouts) > 0 {
+		return val.Layouts
+	}
+
+	return []string{time.RFC3339}
+}
+
+// Set set the value
+func (val *Time) Set(value string) error {
+	parsed, err := parseTime(value, val.layouts())
+	if err != nil {
+		return err
+	}
+
+	val.Value = parsed
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the value, in the first layout
+func (val *Time) String() string {
+	if val.ValueSet {
+		return formatTime(val.Value, val.layouts()[0])
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default value, in the first layout
+func (val *Time) DefaultValueString() string {
+	return formatTime(val.DefaultValue, val.layouts()[0])
+}
+
+// IsBoolValue check if value is boolean
+func (val *Time) IsBoolValue() bool {
+	return false
+}
+
+// NewDate create a date option, accepting the DateLayout layout
+func NewDate(long string, short rune, description string, defaultValue time.Time) *Option {
+	return &Option{
+		Long:        long,
+		Short:       short,
+		Description: description,
+		Value:       &Date{DefaultValue: defaultValue},
+	}
+}
+
+// DateValue return the value of a Date option
+func DateValue(option *Option) (time.Time, error) {
+	if value, ok := option.Value.(*Date); ok {
+		if value.ValueSet {
+			return value.Value, nil
+		}
+
+		return value.DefaultValue, nil
+	}
+
+	return time.Time{}, fmt.Errorf("Not a date option")
+}
+
+// layouts the accepted layouts
+func (val *Date) layouts() []string {
+	if len(val.Layouts) > 0 {
+		return val.Layouts
+	}
+
+	return []string{DateLayout}
+}
+
+// Set set the value, truncated to the day (UTC)
+func (val *Date) Set(value string) error {
+	parsed, err := parseTime(value, val.layouts())
+	if err != nil {
+		return err
+	}
+
+	val.Value = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC)
+	val.ValueSet = true
+
+	return nil
+}
+
+// String representation of the value, in the first layout
+func (val *Date) String() string {
+	if val.ValueSet {
+		return formatTime(val.Value, val.layouts()[0])
+	}
+
+	return val.DefaultValueString()
+}
+
+// DefaultValueString string representation of the default value, in the first layout
+func (val *Date) DefaultValueString() string {
+	return formatTime(val.DefaultValue, val.layouts()[0])
+}
+
+// IsBoolValue check if value is boolean
+func (val *Date) IsBoolValue() bool {
+	return false
+}
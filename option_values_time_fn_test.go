@@ -0,0 +1,156 @@
+package flags
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"90s":    90 * time.Second,
+		"1h30m":  90 * time.Minute,
+		"2d":     48 * time.Hour,
+		"1w":     7 * 24 * time.Hour,
+		"1w2d3h": 9*24*time.Hour + 3*time.Hour,
+		"1.5d":   36 * time.Hour,
+		"-1d12h": -36 * time.Hour,
+		"+1d":    24 * time.Hour,
+		"0":      0,
+	} {
+		duration, err := parseDuration(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, duration, value)
+	}
+
+	// math.MaxInt64 is 15250w1d23h47m16.854775807s
+	for _, value := range []string{
+		"", "1", "1x", "1dx", "1d-2h", "1d+2h", "d", "1000000000w", "15251w", "15250w2d", "15250w1d24h",
+	} {
+		_, err := parseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for duration, expected := range map[time.Duration]string{
+		0:                              "0s",
+		90 * time.Second:               "1m30s",
+		90 * time.Minute:               "1h30m",
+		2 * time.Hour:                  "2h",
+		10 * time.Minute:               "10m",
+		1500 * time.Millisecond:        "1.5s",
+		48 * time.Hour:                 "2d",
+		9*24*time.Hour + 3*time.Hour:   "1w2d3h",
+		-36 * time.Hour:                "-1d12h",
+		24*time.Hour + 90*time.Second:  "1d1m30s",
+		7*24*time.Hour + 5*time.Minute: "1w5m",
+		math.MaxInt64:                  "15250w1d23h47m16.854775807s",
+		-math.MaxInt64:                 "-15250w1d23h47m16.854775807s",
+	} {
+		assert.Equal(t, expected, formatDuration(duration), duration)
+
+		parsed, err := parseDuration(expected)
+		assert.NoError(t, err, expected)
+		assert.Equal(t, duration, parsed, expected)
+	}
+
+	assert.Equal(t, "-15250w1d23h47m16.854775808s", formatDuration(math.MinInt64))
+}
+
+func TestDurationValue(t *testing.T) {
+	opt := NewDuration("timeout", 't', "description", 90*time.Minute)
+	assert.Equal(t, "timeout", opt.Long)
+	assert.Equal(t, 't', opt.Short)
+	assert.Equal(t, "description", opt.Description)
+	assert.Equal(t, "1h30m", opt.Value.DefaultValueString())
+	assert.Equal(t, "1h30m", opt.Value.String())
+	assert.False(t, opt.Value.IsBoolValue())
+
+	assert.NoError(t, opt.Value.Set("2d"))
+	assert.Error(t, opt.Value.Set("2 days"))
+
+	value, err := DurationValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, value)
+	assert.Equal(t, "2d", opt.Value.String())
+
+	_, err = DurationValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestTimeValue(t *testing.T) {
+	now := time.Date(2020, 6, 8, 12, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	defer func() { timeNow = time.Now }()
+
+	opt := NewTime("since", 's', "", time.Time{})
+	assert.Equal(t, "", opt.Value.DefaultValueString())
+	assert.False(t, opt.Value.IsBoolValue())
+
+	assert.NoError(t, opt.Value.Set("2020-06-01T10:00:00+02:00"))
+
+	value, err := TimeValue(opt)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2020, 6, 1, 8, 0, 0, 0, time.UTC).Equal(value))
+	assert.Equal(t, "2020-06-01T10:00:00+02:00", opt.Value.String())
+
+	for relative, expected := range map[string]time.Time{
+		"now":      now,
+		"now-1h":   now.Add(-time.Hour),
+		"now+1d":   now.Add(24 * time.Hour),
+		"now-1w2d": now.Add(-9 * 24 * time.Hour),
+	} {
+		assert.NoError(t, opt.Value.Set(relative), relative)
+
+		value, _ = TimeValue(opt)
+		assert.Equal(t, expected, value, relative)
+	}
+
+	assert.EqualError(
+		t,
+		opt.Value.Set("yesterday"),
+		`expected a time like "2006-01-02T15:04:05Z07:00" or relative to now (i.e. "now-1h")`,
+	)
+	assert.EqualError(t, opt.Value.Set("now--1h"), `invalid relative time "now--1h"`)
+	assert.EqualError(t, opt.Value.Set("now-1x"), `invalid relative time "now-1x"`)
+
+	opt.Value = &Time{DefaultValue: now, Layouts: []string{"2006-01-02 15:04", time.RFC3339}}
+	assert.Equal(t, "2020-06-08 12:30", opt.Value.DefaultValueString())
+	assert.NoError(t, opt.Value.Set("2020-06-01T10:00:00Z"))
+	assert.Equal(t, "2020-06-01 10:00", opt.Value.String())
+
+	_, err = TimeValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
+
+func TestDateValue(t *testing.T) {
+	now := time.Date(2020, 6, 8, 12, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	defer func() { timeNow = time.Now }()
+
+	opt := NewDate("day", 'd', "", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2020-01-01", opt.Value.DefaultValueString())
+	assert.False(t, opt.Value.IsBoolValue())
+
+	assert.NoError(t, opt.Value.Set("2020-06-01"))
+
+	value, err := DateValue(opt)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), value)
+	assert.Equal(t, "2020-06-01", opt.Value.String())
+
+	assert.NoError(t, opt.Value.Set("now-7d"))
+
+	value, _ = DateValue(opt)
+	assert.Equal(t, time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), value)
+
+	assert.Error(t, opt.Value.Set("2020-06-01T10:00:00Z"))
+
+	_, err = DateValue(NewInt("", EmptyShort, "", 0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package flags
+
+func newParseResult() *ParseResult {
+	return &ParseResult{
+		path:      []*Command{},
+		values:    map[*Option]Value{},
+		sources:   map[*Option]Source{},
+		argValues: map[*Arg]Value{},
+		argRaw:    map[*Arg][]string{},
+		rest:      []string{},
+	}
+}
+
+// addOptions prepare the options' values, copying the definitions' ones
+func (result *ParseResult) addOptions(options []*Option) {
+	for _, option := range options {
+		if _, ok := result.values[option]; !ok {
+			result.values[option] = cloneValue(option.Value)
+		}
+	}
+}
+
+// Path the called commands, from the root's one to the deepest
+func (result *ParseResult) Path() []*Command {
+	return append([]*Command{}, result.path...)
+}
+
+// Command the deepest called command, nil if no command has been called
+func (result *ParseResult) Command() *Command {
+	if len(result.path) == 0 {
+		return nil
+	}
+
+	return result.path[len(result.path)-1]
+}
+
+// Called check if the command has been called (either as the deepest
+// command or as one of its parents)
+func (result *ParseResult) Called(cmd *Command) bool {
+	for _, called := range result.path {
+		if called == cmd {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Value the option's value. If the option is not part of the called
+// commands' tree, a copy of its default value is returned
+func (result *ParseResult) Value(option *Option) Value {
+	if value, ok := result.values[option]; ok {
+		return value
+	}
+
+	return cloneValue(option.Value)
+}
+
+// Option a copy of the option bound to its parsed value, to be used with
+// the value getters (i.e. BoolValue(result.Option(opt)))
+func (result *ParseResult) Option(option *Option) *Option {
+	bound := *option
+	bound.Value = result.Value(option)
+
+	return &bound
+}
+
+// IsSet check if the option has been explicitly set by the user
+// (via the arguments, its environment variable or the configuration file)
+func (result *ParseResult) IsSet(option *Option) bool {
+	return result.Source(option).Kind != SourceDefault
+}
+
+// Source the source that last set the option's value
+// (SourceDefault if it has not been set)
+func (result *ParseResult) Source(option *Option) Source {
+	return result.sources[option]
+}
+
+// Arg a copy of the positional argument bound to its parsed value(s)
+func (result *ParseResult) Arg(arg *Arg) *Arg {
+	bound := *arg
+	bound.values = append([]string{}, result.argRaw[arg]...)
+
+	if value, ok := result.argValues[arg]; ok {
+		bound.Value = value
+	} else if arg.Value != nil {
+		bound.Value = cloneValue(arg.Value)
+	}
+
+	return &bound
+}
+
+// Rest the arguments following the "--" terminator, if the called command
+// (or the root, if none) is a passthrough one
+func (result *ParseResult) Rest() []string {
+	return append([]string{}, result.rest...)
+}
@@ -0,0 +1,79 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testClonerValue struct {
+	*Bool
+	cloned bool
+}
+
+func (val *testClonerValue) Clone() Value {
+	return &testClonerValue{Bool: &Bool{DefaultValue: val.DefaultValue}, cloned: true}
+}
+
+func TestParseResultValue(t *testing.T) {
+	opt := NewString("opt", 'o', "", "default")
+	result := newParseResult()
+
+	// not part of the parse: a copy of the default value
+	value := result.Value(opt)
+	assert.Equal(t, "default", value.String())
+	assert.False(t, value == opt.Value)
+
+	result.addOptions([]*Option{opt})
+	assert.NoError(t, result.Value(opt).Set("value"))
+	assert.Equal(t, "value", result.Value(opt).String())
+	assert.Equal(t, "default", opt.Value.String())
+}
+
+func TestParseResultOption(t *testing.T) {
+	opt := NewBool("opt", 'o', "description", false)
+	result := newParseResult()
+	result.addOptions([]*Option{opt})
+	assert.NoError(t, result.Value(opt).Set("true"))
+
+	bound := result.Option(opt)
+	assert.Equal(t, opt.Long, bound.Long)
+	assert.Equal(t, opt.Short, bound.Short)
+	assert.Equal(t, opt.Description, bound.Description)
+
+	val, err := BoolValue(bound)
+	assert.NoError(t, err)
+	assert.True(t, val)
+
+	val, err = BoolValue(opt)
+	assert.NoError(t, err)
+	assert.False(t, val)
+}
+
+func TestParseResultCloner(t *testing.T) {
+	opt := &Option{Long: "opt", Value: &testClonerValue{Bool: &Bool{}}}
+	result := newParseResult()
+	result.addOptions([]*Option{opt})
+
+	clone, ok := result.Value(opt).(*testClonerValue)
+	assert.True(t, ok)
+	assert.True(t, clone.cloned)
+}
+
+func TestParseResultPath(t *testing.T) {
+	cmd := &Command{Name: "cmd"}
+	other := &Command{Name: "other"}
+	result := newParseResult()
+	assert.Nil(t, result.Command())
+
+	result.path = append(result.path, cmd)
+
+	assert.Equal(t, cmd, result.Command())
+	assert.True(t, result.Called(cmd))
+	assert.False(t, result.Called(other))
+
+	// the path is a copy
+	path := result.Path()
+	path[0] = other
+	assert.True(t, result.Called(cmd))
+}
@@ -0,0 +1,419 @@
+package flags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// parser the state of a single parse
+type parser struct {
+	flags       *Flags
+	result      *ParseResult
+	command     *Command // the current command, nil for the root
+	positionals []string
+	argIndex    int // the index of the argument being parsed
+}
+
+func newParser(flags *Flags) *parser {
+	p := &parser{
+		flags:       flags,
+		result:      newParseResult(),
+		positionals: []string{},
+	}
+
+	p.result.addOptions(flags.Options)
+
+	return p
+}
+
+// commands the sub-commands of the current command (or the root's ones)
+func (p *parser) commands() []*Command {
+	return p.flags.subCommands(p.command)
+}
+
+// options the options of the current command (or the root's ones),
+// followed by the persistent ones inherited from its parents
+func (p *parser) options() []*Option {
+	if p.command == nil {
+		return p.flags.Options
+	}
+
+	options := append([]*Option{}, p.command.Options...)
+
+	return append(options, p.flags.persistentOptions(p.result.path)...)
+}
+
+// args the positional arguments of the current command (or the root's ones)
+func (p *parser) args() []*Arg {
+	if p.command != nil {
+		return p.command.Args
+	}
+
+	return p.flags.Args
+}
+
+// isPassthrough check if the current command (or root) is a passthrough one
+func (p *parser) isPassthrough() bool {
+	if p.command != nil {
+		return p.command.Passthrough
+	}
+
+	return p.flags.Passthrough
+}
+
+func (p *parser) parse(args []string) error {
+	// guard condition
+	if len(args) == 0 {
+		return nil
+	}
+
+	arg := args[0]
+	nextArg := ""
+	hasNextArg := len(args) > 1
+
+	// End of options: the remaining arguments are either passed through
+	// verbatim or considered positionals, even if they look like options
+	if arg == "--" {
+		if p.isPassthrough() {
+			p.result.rest = append(p.result.rest, args[1:]...)
+		} else {
+			p.positionals = append(p.positionals, args[1:]...)
+		}
+
+		return nil
+	}
+
+	if hasNextArg {
+		nextArg = args[1]
+	}
+
+	if isOption(arg) && !p.isNegativeNumber(arg) {
+		var nextArgConsumed bool
+
+		var err error
+
+		if isShortOption(arg) {
+			nextArgConsumed, err = p.parseShortOptions(arg, nextArg, hasNextArg)
+		} else {
+			nextArgConsumed, err = p.parseLongOption(arg, nextArg, hasNextArg)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if nextArgConsumed {
+			return p.skip(args, 2)
+		}
+
+		return p.skip(args, 1)
+	}
+
+	// Not an Option, so it's a Command (unless positionals have already been given)
+	if len(p.positionals) == 0 {
+		if command := findCommand(p.commands(), arg); command != nil {
+			p.command = command
+			p.result.path = append(p.result.path, command)
+			p.result.addOptions(command.Options)
+
+			return p.skip(args, 1)
+		}
+	}
+
+	if len(p.args()) == 0 {
+		return p.unknownCommand(arg)
+	}
+
+	p.positionals = append(p.positionals, arg)
+
+	return p.skip(args, 1)
+}
+
+// skip continue the parse after the first n arguments
+func (p *parser) skip(args []string, n int) error {
+	p.argIndex += n
+
+	return p.parse(args[n:])
+}
+
+// isNegativeNumber check if the argument is a negative number (i.e. -5, -1.5),
+// which is a positional unless some short option is a digit (i.e. -5 for --top5)
+func (p *parser) isNegativeNumber(arg string) bool {
+	if _, err := strconv.ParseFloat(arg, 64); err != nil || !unicode.IsDigit(rune(arg[1])) {
+		return false
+	}
+
+	for _, option := range p.options() {
+		if unicode.IsDigit(option.Short) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// argSource the source of a value given by the argument being parsed
+func (p *parser) argSource(arg string) Source {
+	return Source{Kind: SourceArgs, ArgIndex: p.argIndex, Arg: arg}
+}
+
+// parseShortOptions parse a short options cluster (i.e. -abc, -ovalue),
+// returning true if the next argument (if any) has been consumed as a value
+func (p *parser) parseShortOptions(arg string, nextArg string, hasNextArg bool) (bool, error) {
+	runes := []rune(arg)
+
+	for i := 1; i < len(runes); i++ {
+		subArg := runes[i]
+		option := findShortOption(p.options(), subArg)
+
+		if option == nil {
+			return false, p.unknownOption(fmt.Sprintf("-%c", subArg), string(subArg))
+		}
+
+		canAccessNextArg := i == (len(runes) - 1)
+
+		if option.Value.IsBoolValue() {
+			if canAccessNextArg && takesNextBoolLiteral(option, nextArg) {
+				return true, p.setOption(option, p.argSource(arg), nextArg)
+			}
+
+			if err := p.setOption(option, p.argSource(arg), "true"); err != nil {
+				return false, err
+			}
+
+			continue
+		}
+
+		// GNU syntax: the rest of the cluster is the option's value (i.e. -ofile)
+		if !canAccessNextArg {
+			return false, p.setOption(option, p.argSource(arg), string(runes[i+1:]))
+		}
+
+		if !hasNextArg {
+			return false, &MissingValueError{Token: fmt.Sprintf("-%c", subArg), Option: option, Path: p.result.Path()}
+		}
+
+		return true, p.setOption(option, p.argSource(arg), nextArg)
+	}
+
+	return false, nil
+}
+
+// parseLongOption parse a long option (i.e. --option, --option=value),
+// returning true if the next argument (if any) has been consumed as a value
+func (p *parser) parseLongOption(arg string, nextArg string, hasNextArg bool) (bool, error) {
+	argName, attachedValue, hasAttachedValue, err := getOptionName(arg)
+	if err != nil {
+		return false, p.unknownOption(arg, strings.TrimPrefix(arg, "--"))
+	}
+
+	option := findLongOption(p.options(), argName)
+	if option == nil {
+		return p.parseNegatedOption(arg, argName, hasAttachedValue)
+	}
+
+	if option.Value.IsBoolValue() {
+		// the values are validated by Value.Set (i.e. --debug=false, --verbose=3)
+		if hasAttachedValue {
+			return false, p.setOption(option, p.argSource(arg), attachedValue)
+		}
+
+		if takesNextBoolLiteral(option, nextArg) {
+			return true, p.setOption(option, p.argSource(arg), nextArg)
+		}
+
+		return false, p.setOption(option, p.argSource(arg), "true")
+	}
+
+	if hasAttachedValue {
+		return false, p.setOption(option, p.argSource(arg), attachedValue)
+	}
+
+	if !hasNextArg {
+		return false, &MissingValueError{Token: arg, Option: option, Path: p.result.Path()}
+	}
+
+	return true, p.setOption(option, p.argSource(arg), nextArg)
+}
+
+// parseNegatedOption parse the negation of a bool option (i.e. --no-color for --color)
+func (p *parser) parseNegatedOption(arg string, argName string, hasAttachedValue bool) (bool, error) {
+	option := findLongOption(p.options(), strings.TrimPrefix(argName, negationPrefix))
+	if !strings.HasPrefix(argName, negationPrefix) || option == nil || !option.Value.IsBoolValue() {
+		return false, p.unknownOption("--"+argName, argName)
+	}
+
+	if hasAttachedValue {
+		return false, &InvalidValueError{
+			Token:  arg,
+			Value:  strings.TrimPrefix(arg, "--"+argName+"="),
+			Option: option,
+			Path:   p.result.Path(),
+			Err:    fmt.Errorf("a negated option does not accept a value"),
+		}
+	}
+
+	return false, p.setOption(option, p.argSource(arg), falseStr)
+}
+
+// takesNextBoolLiteral check if the bool option takes its value from the next
+// argument (i.e. --debug false), unless it is a standalone one (see StandaloneBoolValue)
+func takesNextBoolLiteral(option *Option, nextArg string) bool {
+	if standalone, ok := option.Value.(StandaloneBoolValue); ok && standalone.IsStandalone() {
+		return false
+	}
+
+	return isBoolLiteral(nextArg)
+}
+
+// resultOptions the options of the root and of the called commands, in definition order
+func (p *parser) resultOptions() []*Option {
+	options := append([]*Option{}, p.flags.Options...)
+
+	for _, cmd := range p.result.path {
+		options = append(options, cmd.Options...)
+	}
+
+	return options
+}
+
+// setOption set the option's value in the parse result, recording its source
+func (p *parser) setOption(option *Option, source Source, value string) error {
+	if err := p.result.Value(option).Set(value); err != nil {
+		return &InvalidValueError{Token: source.token(), Value: value, Option: option, Path: p.result.Path(), Err: err}
+	}
+
+	p.result.sources[option] = source
+
+	return nil
+}
+
+// bindArgs set the positional arguments' values of the called command (or root)
+func (p *parser) bindArgs() error {
+	args := p.args()
+
+	for _, arg := range args {
+		p.result.argValues[arg] = cloneValue(arg.Value)
+		p.result.argRaw[arg] = []string{}
+	}
+
+	for i, positional := range p.positionals {
+		if len(args) == 0 {
+			return p.unknownCommand(positional)
+		}
+
+		arg := args[len(args)-1]
+
+		switch {
+		case i < len(args):
+			arg = args[i]
+		case !arg.Variadic:
+			return &UnexpectedArgumentError{Token: positional, Path: p.result.Path()}
+		}
+
+		if err := p.result.argValues[arg].Set(positional); err != nil {
+			return &InvalidValueError{Token: positional, Value: positional, Arg: arg, Path: p.result.Path(), Err: err}
+		}
+
+		p.result.argRaw[arg] = append(p.result.argRaw[arg], positional)
+	}
+
+	return nil
+}
+
+// checkRequired check that all the required options of the called commands
+// (or root) and the required positional arguments have been given
+func (p *parser) checkRequired() error {
+	err := &MissingRequiredError{Options: []*Option{}, Args: []*Arg{}, Path: p.result.Path()}
+
+	for _, option := range p.resultOptions() {
+		if option.Required && !p.result.IsSet(option) {
+			err.Options = append(err.Options, option)
+		}
+	}
+
+	for _, arg := range p.args() {
+		if arg.Required && len(p.result.argRaw[arg]) == 0 {
+			err.Args = append(err.Args, arg)
+		}
+	}
+
+	if len(err.Options) > 0 || len(err.Args) > 0 {
+		return err
+	}
+
+	return nil
+}
+
+// unknownCommand the error for an unknown command, with the similar sub-commands
+func (p *parser) unknownCommand(token string) error {
+	candidates := []string{}
+	for _, command := range p.commands() {
+		candidates = append(candidates, command.Name)
+	}
+
+	return &UnknownCommandError{Token: token, Path: p.result.Path(), Suggestions: p.suggest(token, candidates)}
+}
+
+// unknownOption the error for an unknown option, with the similar options (long and short)
+func (p *parser) unknownOption(token string, name string) error {
+	candidates := []string{}
+
+	for _, option := range p.options() {
+		if option.Long != "" {
+			candidates = append(candidates, "--"+option.Long)
+		}
+
+		if option.Short != EmptyShort {
+			candidates = append(candidates, fmt.Sprintf("-%c", option.Short))
+		}
+	}
+
+	return &UnknownOptionError{Token: token, Name: name, Path: p.result.Path(), Suggestions: p.suggest(name, candidates)}
+}
+
+// suggest the candidates similar to the name, unless disabled
+func (p *parser) suggest(name string, candidates []string) []string {
+	if p.flags.DisableSuggestions {
+		return []string{}
+	}
+
+	maxDistance := p.flags.SuggestionsDistance
+	if maxDistance == 0 {
+		maxDistance = DefaultSuggestionsDistance
+	}
+
+	return suggestions(name, candidates, maxDistance)
+}
+
+func findCommand(commands []*Command, name string) *Command {
+	for _, command := range commands {
+		if command.Name == name {
+			return command
+		}
+	}
+
+	return nil
+}
+
+func findShortOption(options []*Option, short rune) *Option {
+	for _, option := range options {
+		if option.Short == short {
+			return option
+		}
+	}
+
+	return nil
+}
+
+func findLongOption(options []*Option, long string) *Option {
+	for _, option := range options {
+		if option.Long != "" && option.Long == long {
+			return option
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserBindArgs(t *testing.T) {
+	src := &Arg{Name: "SRC", Value: &String{}, Required: true}
+	count := &Arg{Name: "COUNT", Value: &Int{}}
+	files := &Arg{Name: "FILES", Value: &String{}, Variadic: true}
+	flags := &Flags{Args: []*Arg{src, count, files}}
+
+	p := newParser(flags)
+	p.positionals = []string{"a", "3", "b", "c"}
+	assert.NoError(t, p.bindArgs())
+	assert.Equal(t, []string{"a"}, p.result.Arg(src).Values())
+	assert.Equal(t, "3", p.result.Arg(count).Value.String())
+	assert.Equal(t, []string{"b", "c"}, p.result.Arg(files).Values())
+
+	p = newParser(flags)
+	p.positionals = []string{"a"}
+	assert.NoError(t, p.bindArgs())
+	assert.Empty(t, p.result.Arg(files).Values())
+
+	p = newParser(flags)
+	assert.NoError(t, p.bindArgs())
+	assert.Error(t, p.checkRequired())
+
+	p = newParser(flags)
+	p.positionals = []string{"a", "not-a-number"}
+	assert.Error(t, p.bindArgs())
+
+	p = newParser(&Flags{Args: []*Arg{src}})
+	p.positionals = []string{"a", "b"}
+	assert.Error(t, p.bindArgs())
+
+	p = newParser(&Flags{})
+	p.positionals = []string{"a"}
+	assert.Error(t, p.bindArgs())
+}
@@ -0,0 +1,43 @@
+package flags
+
+import "fmt"
+
+// String the source's kind name
+func (kind SourceKind) String() string {
+	switch kind {
+	case SourceArgs:
+		return "args"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	default:
+		return "default"
+	}
+}
+
+// String the source's description (i.e. `args[2] "--num=3"`, `env NUM`, `config app.json:build.num`)
+func (source Source) String() string {
+	switch source.Kind {
+	case SourceArgs:
+		return fmt.Sprintf("%s[%d] %q", source.Kind, source.ArgIndex, source.Arg)
+	case SourceEnv, SourceConfig:
+		return fmt.Sprintf("%s %s", source.Kind, source.token())
+	default:
+		return source.Kind.String()
+	}
+}
+
+// token the source's token, as reported by the errors
+func (source Source) token() string {
+	switch source.Kind {
+	case SourceArgs:
+		return source.Arg
+	case SourceEnv:
+		return source.EnvVar
+	case SourceConfig:
+		return source.File + ":" + source.Key
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,51 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceString(t *testing.T) {
+	assert.Equal(t, "default", Source{}.String())
+	assert.Equal(t, `args[2] "--num=3"`, Source{Kind: SourceArgs, ArgIndex: 2, Arg: "--num=3"}.String())
+	assert.Equal(t, "env NUM", Source{Kind: SourceEnv, EnvVar: "NUM"}.String())
+	assert.Equal(t, "config app.json:build.num", Source{Kind: SourceConfig, File: "app.json", Key: "build.num"}.String())
+}
+
+func TestFlagsParseSources(t *testing.T) {
+	path, cleanup := testConfigFile(t, "config.json", `{"name": "config", "build": {"tags": "a"}}`)
+	defer cleanup()
+
+	config := NewString("config", 'c', "", path)
+	num := NewInt("num", 'n', "", 1)
+	name := NewString("name", EmptyShort, "", "")
+	debug := NewBool("debug", 'd', "", false)
+	token := NewString("token", EmptyShort, "", "")
+	token.EnvVar = "TOKEN"
+	tags := NewString("tags", EmptyShort, "", "")
+	build := &Command{Name: "build"}
+	build.WithOptions(tags)
+
+	flags := Flags{ConfigOption: config}
+	flags.WithOptions(config, num, name, debug, token)
+	flags.WithCommands(build)
+	flags.LookupEnv = testLookupEnv(map[string]string{"TOKEN": "secret"})
+
+	result, err := flags.ParseArgs([]string{"-d", "--num", "3", "build"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, Source{}, result.Source(config))
+	assert.Equal(t, Source{Kind: SourceArgs, ArgIndex: 0, Arg: "-d"}, result.Source(debug))
+	assert.Equal(t, Source{Kind: SourceArgs, ArgIndex: 1, Arg: "--num"}, result.Source(num))
+	assert.Equal(t, Source{Kind: SourceEnv, EnvVar: "TOKEN"}, result.Source(token))
+	assert.Equal(t, Source{Kind: SourceConfig, File: path, Key: "name"}, result.Source(name))
+	assert.Equal(t, Source{Kind: SourceConfig, File: path, Key: "build.tags"}, result.Source(tags))
+	assert.False(t, result.IsSet(config))
+	assert.True(t, result.IsSet(tags))
+
+	// the last source wins
+	result, err = flags.ParseArgs([]string{"--num", "3", "--num=4"})
+	assert.NoError(t, err)
+	assert.Equal(t, Source{Kind: SourceArgs, ArgIndex: 2, Arg: "--num=4"}, result.Source(num))
+}
@@ -1,5 +1,9 @@
 package flags
 
+type testContextKeyType string
+
+const testContextKey testContextKeyType = "test"
+
 type testStringWriter struct {
 	Value string
 }
@@ -0,0 +1,126 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// Min the numeric value must be greater than or equal to min
+func Min(min float64) Validator {
+	return func(value Value) error {
+		number, err := strconv.ParseFloat(value.String(), 64)
+		if err != nil {
+			return fmt.Errorf("not a number")
+		}
+
+		if number < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+
+		return nil
+	}
+}
+
+// Max the numeric value must be lower than or equal to max
+func Max(max float64) Validator {
+	return func(value Value) error {
+		number, err := strconv.ParseFloat(value.String(), 64)
+		if err != nil {
+			return fmt.Errorf("not a number")
+		}
+
+		if number > max {
+			return fmt.Errorf("must be at most %v", max)
+		}
+
+		return nil
+	}
+}
+
+// OneOf the value must be one of the choices
+func OneOf(choices ...string) Validator {
+	return func(value Value) error {
+		for _, choice := range choices {
+			if value.String() == choice {
+				return nil
+			}
+		}
+
+		return choicesError(choices)
+	}
+}
+
+// Matches the value must match the regular expression.
+// It panics if the expression is not valid
+func Matches(pattern string) Validator {
+	expression := regexp.MustCompile(pattern)
+
+	return func(value Value) error {
+		if !expression.MatchString(value.String()) {
+			return fmt.Errorf(`must match "%s"`, pattern)
+		}
+
+		return nil
+	}
+}
+
+// NonEmpty the value must not be empty
+func NonEmpty() Validator {
+	return func(value Value) error {
+		if value.String() == "" {
+			return fmt.Errorf("must not be empty")
+		}
+
+		return nil
+	}
+}
+
+// FileExists the value must be the path of an existing file (or directory)
+func FileExists() Validator {
+	return func(value Value) error {
+		if _, err := os.Stat(value.String()); err != nil {
+			return fmt.Errorf(`file "%s" does not exist`, value.String())
+		}
+
+		return nil
+	}
+}
+
+// validate run the validators of the given options and of the called
+// commands (or root), collecting all of their errors
+func (p *parser) validate() error {
+	errs := []error{}
+
+	for _, option := range p.resultOptions() {
+		if !p.result.IsSet(option) {
+			continue
+		}
+
+		value := p.result.Value(option)
+
+		for _, validator := range option.Validators {
+			if err := validator(value); err != nil {
+				errs = append(errs, &ValidationError{Option: option, Value: value.String(), Path: p.result.Path(), Err: err})
+			}
+		}
+	}
+
+	validators := append([]CommandValidator{}, p.flags.Validators...)
+	for _, cmd := range p.result.path {
+		validators = append(validators, cmd.Validators...)
+	}
+
+	for _, validator := range validators {
+		if err := validator(p.result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationErrors{Errors: errs, Path: p.result.Path()}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidators(t *testing.T) {
+	assert.NoError(t, Min(1)(&Int{Value: 1, ValueSet: true}))
+	assert.EqualError(t, Min(1)(&Int{Value: 0, ValueSet: true}), "must be at least 1")
+	assert.EqualError(t, Min(1)(&String{Value: "a", ValueSet: true}), "not a number")
+	assert.NoError(t, Max(1.5)(&Float64{Value: 1.5, ValueSet: true}))
+	assert.EqualError(t, Max(1.5)(&Float64{Value: 2, ValueSet: true}), "must be at most 1.5")
+
+	assert.NoError(t, OneOf("json", "yaml")(&String{Value: "yaml", ValueSet: true}))
+	assert.EqualError(t, OneOf("json", "yaml")(&String{Value: "xml", ValueSet: true}), `must be one of "json", "yaml"`)
+
+	assert.NoError(t, Matches("^[a-z]+$")(&String{Value: "abc", ValueSet: true}))
+	assert.EqualError(t, Matches("^[a-z]+$")(&String{Value: "a1", ValueSet: true}), `must match "^[a-z]+$"`)
+	assert.Panics(t, func() { Matches("(") })
+
+	assert.NoError(t, NonEmpty()(&String{Value: "a", ValueSet: true}))
+	assert.EqualError(t, NonEmpty()(&String{ValueSet: true}), "must not be empty")
+
+	file, err := ioutil.TempFile("", "flags")
+	assert.NoError(t, err)
+	file.Close()
+
+	defer os.Remove(file.Name())
+
+	assert.NoError(t, FileExists()(&String{Value: file.Name(), ValueSet: true}))
+	assert.EqualError(
+		t,
+		FileExists()(&String{Value: file.Name() + ".missing", ValueSet: true}),
+		fmt.Sprintf(`file "%s.missing" does not exist`, file.Name()),
+	)
+}
+
+func TestFlagsParseValidators(t *testing.T) {
+	port := NewInt("port", 'p', "", 0)
+	port.Validators = []Validator{Min(1), Max(65535)}
+	name := NewString("name", 'n', "", "")
+	name.Validators = []Validator{NonEmpty()}
+	min := NewInt("min", EmptyShort, "", 0)
+	max := NewInt("max", EmptyShort, "", 10)
+	rangeErr := errors.New("--min must be lower than --max")
+	serve := &Command{Name: "serve"}
+	serve.WithOptions(min, max)
+	serve.Validators = []CommandValidator{func(result *ParseResult) error {
+		minVal, _ := IntValue(result.Option(min))
+		maxVal, _ := IntValue(result.Option(max))
+
+		if minVal >= maxVal {
+			return rangeErr
+		}
+
+		return nil
+	}}
+
+	flags := Flags{}
+	flags.WithOptions(port, name)
+	flags.WithCommands(serve)
+
+	// the values not given are not validated
+	_, err := flags.ParseArgs([]string{"serve"})
+	assert.NoError(t, err)
+
+	_, err = flags.ParseArgs([]string{"-p", "0", "--name=", "serve", "--min", "10"})
+
+	var validationErrs *ValidationErrors
+
+	assert.True(t, errors.As(err, &validationErrs))
+	assert.Len(t, validationErrs.Errors, 3)
+	assert.Equal(t, []*Command{serve}, validationErrs.Path)
+	assert.Equal(
+		t,
+		`validation failed (command "serve"): invalid value "0" for option "--port": must be at least 1; `+
+			`invalid value "" for option "--name": must not be empty; --min must be lower than --max`,
+		err.Error(),
+	)
+
+	var validationErr *ValidationError
+
+	assert.True(t, errors.As(validationErrs.Errors[0], &validationErr))
+	assert.Equal(t, port, validationErr.Option)
+	assert.Equal(t, "0", validationErr.Value)
+	assert.Equal(t, rangeErr, validationErrs.Errors[2])
+
+	_, err = flags.ParseArgs([]string{"-p", "8080", "serve", "--min", "1"})
+	assert.NoError(t, err)
+}